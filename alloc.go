@@ -0,0 +1,23 @@
+package logs
+
+import "runtime"
+
+// LogAlloc 执行fn并以Debug等级输出其执行期间的内存分配次数及字节数（其他协程的分配也会计入），
+// 前后各调用一次runtime.ReadMemStats，会短暂暂停所有协程，不宜用于热路径；Debug等级未启用时直接执行fn
+func LogAlloc(logger *Logger, name string, fn func()) error {
+	if !logger.enabled(LogLevelDebug) {
+		fn()
+		return nil
+	}
+
+	var before, after runtime.MemStats
+	runtime.ReadMemStats(&before)
+	fn()
+	runtime.ReadMemStats(&after)
+	return logger.Debug(
+		1,
+		"msg", name,
+		"allocs", after.Mallocs-before.Mallocs,
+		"alloc_bytes", after.TotalAlloc-before.TotalAlloc,
+	)
+}
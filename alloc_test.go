@@ -0,0 +1,40 @@
+//go:build !nodebug
+
+package logs
+
+import "testing"
+
+// 保存分配结果，避免被编译器优化掉
+var allocSink []byte
+
+func TestLogAlloc(t *testing.T) {
+	logger, _ := newTestLogger(t, LogLevelDebug)
+	entries := logger.Capture(func() {
+		_ = LogAlloc(logger, "block", func() { allocSink = make([]byte, 1<<20) })
+	})
+	if len(entries) != 1 {
+		t.Fatalf("want 1 record, got %d", len(entries))
+	}
+	fields := make(map[string]any)
+	for _, field := range entries[0].Fields {
+		fields[field.Key] = field.Value
+	}
+	if fields["msg"] != "block" {
+		t.Fatalf("want msg block, got %v", fields["msg"])
+	}
+	if allocs, ok := fields["allocs"].(uint64); !ok || allocs == 0 {
+		t.Fatalf("want a positive allocation count, got %v", fields["allocs"])
+	}
+	if bytes, ok := fields["alloc_bytes"].(uint64); !ok || bytes < 1<<20 {
+		t.Fatalf("want at least %d allocated bytes, got %v", 1<<20, fields["alloc_bytes"])
+	}
+}
+
+func TestLogAllocDisabled(t *testing.T) {
+	logger, buf := newTestLogger(t, LogLevelInfo)
+	var called bool
+	_ = LogAlloc(logger, "block", func() { called = true })
+	if !called || buf.Len() != 0 {
+		t.Fatalf("fn should run without logging when Debug is off, called %v, output %q", called, buf.String())
+	}
+}
@@ -0,0 +1,37 @@
+package logs
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+)
+
+var attachmentDir atomic.Value
+
+// SetAttachmentDir 设置附件的存放目录
+func SetAttachmentDir(dir string) {
+	attachmentDir.Store(dir)
+}
+
+// Attach 将data写入附件目录（以sha256命名），返回引用该附件的字段，写入失败时字段中包含异常信息
+func Attach(key string, data []byte) Field {
+	sum := sha256.Sum256(data)
+	hash := hex.EncodeToString(sum[:])
+
+	dir, _ := attachmentDir.Load().(string)
+	if dir == "" {
+		return Field{Key: key, Value: []Field{{Key: "sha256", Value: hash}, {Key: "error", Value: errors.New("attachment dir not set")}}}
+	}
+	path := filepath.Join(dir, hash)
+	err := os.MkdirAll(dir, 0755)
+	if err == nil {
+		err = os.WriteFile(path, data, 0644)
+	}
+	if err != nil {
+		return Field{Key: key, Value: []Field{{Key: "sha256", Value: hash}, {Key: "error", Value: err}}}
+	}
+	return Field{Key: key, Value: []Field{{Key: "path", Value: path}, {Key: "sha256", Value: hash}, {Key: "size", Value: len(data)}}}
+}
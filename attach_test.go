@@ -0,0 +1,53 @@
+package logs
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestAttach(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "attachments")
+	SetAttachmentDir(dir)
+	defer SetAttachmentDir("")
+
+	data := []byte("hello\x00world")
+	sum := sha256.Sum256(data)
+	hash := hex.EncodeToString(sum[:])
+
+	field := Attach("payload", data)
+	if field.Key != "payload" {
+		t.Fatalf("want key payload, got %q", field.Key)
+	}
+	want := []Field{{Key: "path", Value: filepath.Join(dir, hash)}, {Key: "sha256", Value: hash}, {Key: "size", Value: len(data)}}
+	got, ok := field.Value.([]Field)
+	if !ok || len(got) != len(want) {
+		t.Fatalf("unexpected field value: %#v", field.Value)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("field %d: want %v, got %v", i, want[i], got[i])
+		}
+	}
+
+	// 附件内容按原样写入
+	content, err := os.ReadFile(filepath.Join(dir, hash))
+	if err != nil {
+		t.Fatalf("read attachment: %v", err)
+	}
+	if string(content) != string(data) {
+		t.Fatalf("want attachment %q, got %q", data, content)
+	}
+}
+
+func TestAttachWithoutDir(t *testing.T) {
+	SetAttachmentDir("")
+	logger, buf := newTestLogger(t, LogLevelDebug)
+	_ = logger.Info(0, Attach("x", []byte("hi")))
+	if !strings.Contains(buf.String(), "error=attachment dir not set") {
+		t.Fatalf("missing dir should be reported in the field: %q", buf.String())
+	}
+}
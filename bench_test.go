@@ -0,0 +1,74 @@
+package logs
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"testing"
+)
+
+func BenchmarkInfo(b *testing.B) {
+	logger := NewLogger(LogLevelDebug, io.Discard)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_ = logger.Info(0, "msg", "hello world")
+	}
+}
+
+func BenchmarkInfoWithFields(b *testing.B) {
+	logger := NewLogger(LogLevelDebug, io.Discard, "app", "bench")
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_ = logger.Info(0, "user", "kkkunny", "id", i, "ok", true)
+	}
+}
+
+func BenchmarkDebugFiltered(b *testing.B) {
+	logger := NewLogger(LogLevelInfo, io.Discard)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_ = logger.Debug(0, "user", "kkkunny", "id", i)
+	}
+}
+
+func BenchmarkErrorWithStack(b *testing.B) {
+	logger := NewLogger(LogLevelDebug, io.Discard)
+	err := ErrorWrap(errors.New("bench"))
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_ = logger.ErrorError(0, err)
+	}
+}
+
+func BenchmarkJSONWithFields(b *testing.B) {
+	logger := NewLogger(LogLevelDebug, io.Discard, "app", "bench")
+	logger.SetFormatter(&JSONFormatter{})
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_ = logger.Info(0, "user", "kkkunny", "id", i, "ok", true)
+	}
+}
+
+func BenchmarkInfof(b *testing.B) {
+	logger := NewLogger(LogLevelDebug, io.Discard)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_ = logger.Infof(0, "user %s id %d", "kkkunny", i)
+	}
+}
+
+// 与Infof相同的输出，经过字段表的通用路径
+func BenchmarkInfoPreformatted(b *testing.B) {
+	logger := NewLogger(LogLevelDebug, io.Discard)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_ = logger.Info(0, "msg", fmt.Sprintf("user %s id %d", "kkkunny", i))
+	}
+}
@@ -0,0 +1,69 @@
+package logs
+
+import (
+	"runtime/debug"
+	"sync"
+)
+
+var (
+	buildInfoLock sync.Mutex
+	buildInfoSet  bool
+	buildVersion  string
+	buildCommit   string
+)
+
+// 读取编译信息，便于测试时替换
+var readBuildInfo = debug.ReadBuildInfo
+
+// SetBuildInfo 设置之后新建的日志管理器附带的version及commit全局字段，传入空字符串的项从debug.ReadBuildInfo读取
+// （主模块版本及vcs.revision），仍为空则不输出该字段
+func SetBuildInfo(version, commit string) {
+	if version == "" || commit == "" {
+		infoVersion, infoCommit := readBuildVersion()
+		if version == "" {
+			version = infoVersion
+		}
+		if commit == "" {
+			commit = infoCommit
+		}
+	}
+
+	buildInfoLock.Lock()
+	defer buildInfoLock.Unlock()
+	buildInfoSet = true
+	buildVersion, buildCommit = version, commit
+}
+
+// 从debug.ReadBuildInfo读取主模块版本及vcs.revision，本地开发构建的版本"(devel)"视为空
+func readBuildVersion() (version, commit string) {
+	info, ok := readBuildInfo()
+	if !ok {
+		return "", ""
+	}
+	if info.Main.Version != "(devel)" {
+		version = info.Main.Version
+	}
+	for _, setting := range info.Settings {
+		if setting.Key == "vcs.revision" {
+			commit = setting.Value
+		}
+	}
+	return version, commit
+}
+
+// 获取编译信息全局字段
+func buildInfoItems() []any {
+	buildInfoLock.Lock()
+	defer buildInfoLock.Unlock()
+	if !buildInfoSet {
+		return nil
+	}
+	var items []any
+	if buildVersion != "" {
+		items = append(items, "version", buildVersion)
+	}
+	if buildCommit != "" {
+		items = append(items, "commit", buildCommit)
+	}
+	return items
+}
@@ -0,0 +1,88 @@
+package logs
+
+import (
+	"runtime/debug"
+	"strings"
+	"testing"
+)
+
+// 恢复编译信息的状态，下次新建日志管理器时重新读取
+func resetBuildInfo(t *testing.T) {
+	t.Helper()
+	t.Cleanup(func() {
+		buildInfoLock.Lock()
+		defer buildInfoLock.Unlock()
+		buildInfoSet, buildVersion, buildCommit = false, "", ""
+		readBuildInfo = debug.ReadBuildInfo
+	})
+}
+
+// 模拟的编译信息
+func fakeBuildInfo(version, revision string) func() (*debug.BuildInfo, bool) {
+	return func() (*debug.BuildInfo, bool) {
+		return &debug.BuildInfo{
+			Main:     debug.Module{Version: version},
+			Settings: []debug.BuildSetting{{Key: "vcs.revision", Value: revision}},
+		}, true
+	}
+}
+
+func TestSetBuildInfo(t *testing.T) {
+	resetBuildInfo(t)
+	SetBuildInfo("v1.2.3", "abc123")
+	logger, buf := newTestLogger(t, LogLevelDebug)
+	_ = logger.Info(0, "x", 1)
+	if !strings.Contains(buf.String(), "| [version]v1.2.3 | [commit]abc123 | x=1") {
+		t.Fatalf("build info should be global fields: %q", buf.String())
+	}
+
+	// 空项从编译信息读取
+	readBuildInfo = fakeBuildInfo("v9.0.0", "deadbeef")
+	SetBuildInfo("v1.2.3", "")
+	logger, buf = newTestLogger(t, LogLevelDebug)
+	_ = logger.Info(0, "x", 1)
+	if !strings.Contains(buf.String(), "| [version]v1.2.3 | [commit]deadbeef | x=1") {
+		t.Fatalf("empty commit should come from build info: %q", buf.String())
+	}
+}
+
+func TestBuildInfoUnset(t *testing.T) {
+	resetBuildInfo(t)
+	buildInfoLock.Lock()
+	buildInfoSet = false
+	buildInfoLock.Unlock()
+
+	// 未调用SetBuildInfo时不附带编译信息
+	readBuildInfo = fakeBuildInfo("v9.0.0", "deadbeef")
+	logger, buf := newTestLogger(t, LogLevelDebug)
+	_ = logger.Info(0, "x", 1)
+	if strings.Contains(buf.String(), "[version]") || strings.Contains(buf.String(), "[commit]") {
+		t.Fatalf("build fields should be absent by default: %q", buf.String())
+	}
+}
+
+func TestBuildInfoFallback(t *testing.T) {
+	resetBuildInfo(t)
+
+	// 调用SetBuildInfo时传入的空项从debug.ReadBuildInfo读取
+	readBuildInfo = fakeBuildInfo("v9.0.0", "deadbeef")
+	SetBuildInfo("", "")
+	logger, buf := newTestLogger(t, LogLevelDebug)
+	_ = logger.Info(0, "x", 1)
+	if !strings.Contains(buf.String(), "| [version]v9.0.0 | [commit]deadbeef | x=1") {
+		t.Fatalf("empty arguments should be read from build info: %q", buf.String())
+	}
+}
+
+func TestBuildInfoFallbackDevel(t *testing.T) {
+	resetBuildInfo(t)
+
+	// 本地开发构建的版本"(devel)"不输出
+	readBuildInfo = fakeBuildInfo("(devel)", "")
+	SetBuildInfo("", "")
+	logger, buf := newTestLogger(t, LogLevelDebug)
+	_ = logger.Info(0, "x", 1)
+	if strings.Contains(buf.String(), "[version]") || strings.Contains(buf.String(), "[commit]") {
+		t.Fatalf("development builds should not add build fields: %q", buf.String())
+	}
+}
@@ -0,0 +1,62 @@
+package logs
+
+import (
+	"fmt"
+	"runtime"
+	"strings"
+)
+
+// 获取从skip开始的n个调用位置，由近及远以" <- "连接
+func callers(skip, n uint) string {
+	if n == 0 {
+		n = 1
+	}
+	pcs := make([]uintptr, n)
+	count := runtime.Callers(int(skip)+2, pcs)
+	if count == 0 {
+		return ""
+	}
+	frames := runtime.CallersFrames(pcs[:count])
+
+	var buf strings.Builder
+	for {
+		frame, more := frames.Next()
+		if buf.Len() > 0 {
+			buf.WriteString(" <- ")
+		}
+		buf.WriteString(fmt.Sprintf("%s:%d", frame.File, frame.Line))
+		if !more {
+			break
+		}
+	}
+	return buf.String()
+}
+
+// 以n层调用位置打印
+func (self *Logger) printCallers(level LogLevel, skip, n uint, a ...any) error {
+	items := self.checkItems(a...)
+	if !self.enabled(level) {
+		return nil
+	}
+	return self.output(level, callers(skip+1, n), items)
+}
+
+// InfoCallers 输出Info信息，位置为调用处起的n层调用位置
+func (self *Logger) InfoCallers(skip, n uint, a ...any) error {
+	return self.printCallers(LogLevelInfo, skip+1, n, a...)
+}
+
+// WarnCallers 输出Warn信息，位置为调用处起的n层调用位置
+func (self *Logger) WarnCallers(skip, n uint, a ...any) error {
+	return self.printCallers(LogLevelWarn, skip+1, n, a...)
+}
+
+// ErrorCallers 输出Error信息，位置为调用处起的n层调用位置
+func (self *Logger) ErrorCallers(skip, n uint, a ...any) error {
+	return self.printCallers(LogLevelError, skip+1, n, a...)
+}
+
+// KeywordCallers 输出Keyword信息，位置为调用处起的n层调用位置
+func (self *Logger) KeywordCallers(skip, n uint, a ...any) error {
+	return self.printCallers(LogLevelKeyword, skip+1, n, a...)
+}
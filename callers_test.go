@@ -0,0 +1,36 @@
+package logs
+
+import (
+	"fmt"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+// 输出两层调用位置，返回输出处的位置
+func logCallers(logger *Logger) string {
+	_, file, line, _ := runtime.Caller(0)
+	_ = logger.InfoCallers(0, 2, "a", 1)
+	return fmt.Sprintf("%s:%d", file, line+1)
+}
+
+func TestInfoCallers(t *testing.T) {
+	logger, buf := newTestLogger(t, LogLevelDebug)
+	_, file, line, _ := runtime.Caller(0)
+	site := logCallers(logger)
+	want := site + " <- " + fmt.Sprintf("%s:%d", file, line+1) + " | "
+	if !strings.Contains(buf.String(), want) {
+		t.Fatalf("want position %q, got %q", want, buf.String())
+	}
+}
+
+func TestInfoCallersSingleFrame(t *testing.T) {
+	logger, buf := newTestLogger(t, LogLevelDebug)
+	_ = logger.InfoCallers(0, 0, "a", 1)
+	if strings.Contains(buf.String(), " <- ") {
+		t.Fatalf("n=0 should render one frame: %q", buf.String())
+	}
+	if !strings.Contains(buf.String(), "callers_test.go:") {
+		t.Fatalf("position should point to the caller: %q", buf.String())
+	}
+}
@@ -0,0 +1,31 @@
+package logs
+
+import (
+	"io"
+	"sync"
+)
+
+// Capture 执行fn并返回期间该日志管理器输出的记录，期间记录不会写入写入器，fn返回后恢复，
+// fn可在多个协程中输出，但需在返回前等待其完成；捕获期间不能并发修改该日志管理器的设置
+func (self *Logger) Capture(fn func()) []Entry {
+	writer, writers, hooks := self.writer, self.writers, self.hooks
+	defer func() {
+		self.writer, self.writers, self.hooks = writer, writers, hooks
+	}()
+
+	var lock sync.Mutex
+	var entries []Entry
+	self.writer = newSink(io.Discard)
+	self.writers = nil
+	self.AddHook(func(entry *Entry) {
+		clone := entry.Clone()
+		lock.Lock()
+		defer lock.Unlock()
+		entries = append(entries, *clone)
+	})
+	fn()
+
+	lock.Lock()
+	defer lock.Unlock()
+	return entries
+}
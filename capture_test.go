@@ -0,0 +1,52 @@
+package logs
+
+import (
+	"sync"
+	"testing"
+)
+
+func TestCapture(t *testing.T) {
+	logger, buf := newTestLogger(t, LogLevelDebug)
+	entries := logger.Capture(func() {
+		_ = logger.Info(0, "a", 1)
+		_ = logger.Warn(0, "b", 2)
+	})
+	if buf.Len() != 0 {
+		t.Fatalf("captured records should not be written: %q", buf.String())
+	}
+	if len(entries) != 2 {
+		t.Fatalf("want 2 records, got %d", len(entries))
+	}
+	if entries[0].Level != LogLevelInfo || entries[0].Fields[0] != (Field{Key: "a", Value: 1}) {
+		t.Fatalf("unexpected first record: %+v", entries[0])
+	}
+	if entries[1].Level != LogLevelWarn || entries[1].Fields[0] != (Field{Key: "b", Value: 2}) {
+		t.Fatalf("unexpected second record: %+v", entries[1])
+	}
+
+	// 返回后恢复写入
+	_ = logger.Info(0, "a", 1)
+	if buf.Len() == 0 {
+		t.Fatal("writer should be restored after capture")
+	}
+}
+
+func TestCaptureConcurrent(t *testing.T) {
+	logger, _ := newTestLogger(t, LogLevelDebug)
+	entries := logger.Capture(func() {
+		var wg sync.WaitGroup
+		for g := 0; g < 8; g++ {
+			wg.Add(1)
+			go func(g int) {
+				defer wg.Done()
+				for i := 0; i < 50; i++ {
+					_ = logger.Info(0, "g", g, "i", i)
+				}
+			}(g)
+		}
+		wg.Wait()
+	})
+	if len(entries) != 8*50 {
+		t.Fatalf("want %d records, got %d", 8*50, len(entries))
+	}
+}
@@ -0,0 +1,47 @@
+package logs
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/gookit/color"
+)
+
+// 写入器是否使用彩色格式，优先级：CLICOLOR_FORCE > NO_COLOR > CLICOLOR > 终端检测；
+// 会读取环境变量并检测终端，只在设置写入器时调用一次，结果缓存在写入目标中
+func colorEnabled(writer io.Writer) bool {
+	if v := os.Getenv("CLICOLOR_FORCE"); v != "" && v != "0" {
+		return true
+	}
+	if os.Getenv("NO_COLOR") != "" {
+		return false
+	}
+	if os.Getenv("CLICOLOR") == "0" {
+		return false
+	}
+	return isTerminalWriter(writer)
+}
+
+// 写入器是否为终端
+func isTerminalWriter(writer io.Writer) bool {
+	file, ok := writer.(*os.File)
+	if !ok {
+		return false
+	}
+	info, err := file.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
+
+// 带颜色的字段值，彩色格式下按其颜色代码渲染
+type colorValue interface {
+	colorCode() string
+}
+
+// 使用颜色代码渲染文本，是否渲染已由colorEnabled决定
+func paint(code, s string) string {
+	if code == "" || s == "" {
+		return s
+	}
+	return fmt.Sprintf(color.FullColorTpl, code, s)
+}
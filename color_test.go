@@ -0,0 +1,67 @@
+package logs
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"testing"
+)
+
+func TestColorEnabled(t *testing.T) {
+	// /dev/null为字符设备，视为终端
+	tty, err := os.OpenFile(os.DevNull, os.O_WRONLY, 0)
+	if err != nil {
+		t.Skipf("open %s: %v", os.DevNull, err)
+	}
+	defer tty.Close()
+	if !isTerminalWriter(tty) {
+		t.Skipf("%s is not a character device", os.DevNull)
+	}
+
+	cases := []struct {
+		name                string
+		force, noColor, cli string
+		tty, want           bool
+	}{
+		{name: "tty", tty: true, want: true},
+		{name: "not tty", tty: false, want: false},
+		{name: "force beats everything", force: "1", noColor: "1", cli: "0", want: true},
+		{name: "force zero is ignored", force: "0", tty: false, want: false},
+		{name: "no color beats clicolor", noColor: "1", cli: "1", tty: true, want: false},
+		{name: "clicolor zero", cli: "0", tty: true, want: false},
+		{name: "clicolor does not force", cli: "1", tty: false, want: false},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			t.Setenv("CLICOLOR_FORCE", c.force)
+			t.Setenv("NO_COLOR", c.noColor)
+			t.Setenv("CLICOLOR", c.cli)
+			var writer io.Writer = &bytes.Buffer{}
+			if c.tty {
+				writer = tty
+			}
+			if got := colorEnabled(writer); got != c.want {
+				t.Fatalf("want colorEnabled %v, got %v", c.want, got)
+			}
+		})
+	}
+}
+
+func TestColorDecidedOnce(t *testing.T) {
+	t.Setenv("CLICOLOR_FORCE", "")
+	t.Setenv("NO_COLOR", "")
+	t.Setenv("CLICOLOR", "")
+	logger, _ := newTestLogger(t, LogLevelDebug)
+	if logger.colorful() {
+		t.Fatal("buffer writer should not be colorful")
+	}
+	// 创建写入器后修改环境变量不影响已有写入器
+	t.Setenv("CLICOLOR_FORCE", "1")
+	if logger.colorful() {
+		t.Fatal("color decision should be made when the writer is set")
+	}
+	logger.SetWriter(&bytes.Buffer{})
+	if !logger.colorful() {
+		t.Fatal("SetWriter should re-decide color")
+	}
+}
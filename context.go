@@ -0,0 +1,46 @@
+package logs
+
+import "context"
+
+// SetDropCancelled 设置带context的日志方法在context已结束时是否跳过渲染及写入
+func (self *Logger) SetDropCancelled(drop bool) {
+	self.dropCancelled = drop
+}
+
+// SetShowDeadline 设置带context的日志方法在context带有截止时间时是否附带剩余时间字段deadline_remaining
+func (self *Logger) SetShowDeadline(show bool) {
+	self.showDeadline = show
+}
+
+// 带context打印
+func (self *Logger) printContext(ctx context.Context, level LogLevel, skip uint, a ...any) error {
+	if self.dropCancelled && ctx.Err() != nil {
+		return nil
+	}
+	if self.showDeadline {
+		if deadline, ok := ctx.Deadline(); ok {
+			a = append(a[:len(a):len(a)], "deadline_remaining", deadline.Sub(now()))
+		}
+	}
+	return self.print(level, skip+1, a...)
+}
+
+// InfoContext 输出Info信息，context已结束时按设置跳过
+func (self *Logger) InfoContext(ctx context.Context, skip uint, a ...any) error {
+	return self.printContext(ctx, LogLevelInfo, skip+1, a...)
+}
+
+// WarnContext 输出Warn信息，context已结束时按设置跳过
+func (self *Logger) WarnContext(ctx context.Context, skip uint, a ...any) error {
+	return self.printContext(ctx, LogLevelWarn, skip+1, a...)
+}
+
+// ErrorContext 输出Error信息，context已结束时按设置跳过
+func (self *Logger) ErrorContext(ctx context.Context, skip uint, a ...any) error {
+	return self.printContext(ctx, LogLevelError, skip+1, a...)
+}
+
+// KeywordContext 输出Keyword信息，context已结束时按设置跳过
+func (self *Logger) KeywordContext(ctx context.Context, skip uint, a ...any) error {
+	return self.printContext(ctx, LogLevelKeyword, skip+1, a...)
+}
@@ -0,0 +1,61 @@
+package logs
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestDropCancelled(t *testing.T) {
+	logger, buf := newTestLogger(t, LogLevelInfo)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	// 默认仍然输出
+	_ = logger.InfoContext(ctx, 0, "a", 1)
+	if !strings.Contains(buf.String(), "context_test.go:") {
+		t.Fatalf("cancelled context should be logged by default: %q", buf.String())
+	}
+
+	buf.Reset()
+	logger.SetDropCancelled(true)
+	_ = logger.InfoContext(ctx, 0, "a", 1)
+	if buf.Len() != 0 {
+		t.Fatalf("record should be skipped for a cancelled context: %q", buf.String())
+	}
+	_ = logger.InfoContext(context.Background(), 0, "a", 1)
+	if buf.Len() == 0 {
+		t.Fatal("record for a live context should be written")
+	}
+}
+
+func TestShowDeadline(t *testing.T) {
+	base := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	defer func(fn func() time.Time) { now = fn }(now)
+	now = func() time.Time { return base.Add(time.Second) }
+
+	logger, buf := newTestLogger(t, LogLevelInfo)
+	ctx, cancel := context.WithDeadline(context.Background(), base.Add(3*time.Second))
+	defer cancel()
+
+	// 默认不输出
+	_ = logger.InfoContext(ctx, 0, "a", 1)
+	if strings.Contains(buf.String(), "deadline_remaining") {
+		t.Fatalf("deadline should be off by default: %q", buf.String())
+	}
+
+	buf.Reset()
+	logger.SetShowDeadline(true)
+	_ = logger.InfoContext(ctx, 0, "a", 1)
+	if !strings.Contains(buf.String(), "a=1 deadline_remaining=2s") {
+		t.Fatalf("want remaining time from the fake clock, got %q", buf.String())
+	}
+
+	// 没有截止时间时不输出
+	buf.Reset()
+	_ = logger.InfoContext(context.Background(), 0, "a", 1)
+	if strings.Contains(buf.String(), "deadline_remaining") {
+		t.Fatalf("context without deadline should not add the field: %q", buf.String())
+	}
+}
@@ -0,0 +1,35 @@
+//go:build !nodebug
+
+package logs
+
+import "context"
+
+// Debug 输出Debug信息
+func (self *Logger) Debug(skip uint, a ...any) error {
+	return self.print(LogLevelDebug, skip+1, a...)
+}
+
+// Debugf 输出Debugf格式化信息
+func (self *Logger) Debugf(skip uint, f string, a ...any) error {
+	return self.printf(LogLevelDebug, skip+1, f, a...)
+}
+
+// DebugError 输出Debug异常信息
+func (self *Logger) DebugError(skip uint, err error) error {
+	return self.printError(LogLevelDebug, skip+1, err)
+}
+
+// DebugCallers 输出Debug信息，位置为调用处起的n层调用位置
+func (self *Logger) DebugCallers(skip, n uint, a ...any) error {
+	return self.printCallers(LogLevelDebug, skip+1, n, a...)
+}
+
+// DebugContext 输出Debug信息，context已结束时按设置跳过
+func (self *Logger) DebugContext(ctx context.Context, skip uint, a ...any) error {
+	return self.printContext(ctx, LogLevelDebug, skip+1, a...)
+}
+
+// DebugAtPos 输出Debug信息，位置原样使用pos（如配置文件中的行）
+func (self *Logger) DebugAtPos(pos string, a ...any) error {
+	return self.printAtPos(LogLevelDebug, pos, a...)
+}
@@ -0,0 +1,38 @@
+//go:build nodebug
+
+// 使用nodebug构建标签时Debug系列方法为空操作，函数体可被编译器内联消除，
+// 但调用处的参数仍会被求值，应避免在参数中进行开销较大的计算
+
+package logs
+
+import "context"
+
+// Debug 输出Debug信息，nodebug构建下为空操作
+func (self *Logger) Debug(skip uint, a ...any) error {
+	return nil
+}
+
+// Debugf 输出Debugf格式化信息，nodebug构建下为空操作
+func (self *Logger) Debugf(skip uint, f string, a ...any) error {
+	return nil
+}
+
+// DebugError 输出Debug异常信息，nodebug构建下为空操作
+func (self *Logger) DebugError(skip uint, err error) error {
+	return nil
+}
+
+// DebugCallers 输出Debug信息，位置为调用处起的n层调用位置，nodebug构建下为空操作
+func (self *Logger) DebugCallers(skip, n uint, a ...any) error {
+	return nil
+}
+
+// DebugContext 输出Debug信息，context已结束时按设置跳过，nodebug构建下为空操作
+func (self *Logger) DebugContext(ctx context.Context, skip uint, a ...any) error {
+	return nil
+}
+
+// DebugAtPos 输出Debug信息，位置原样使用pos（如配置文件中的行），nodebug构建下为空操作
+func (self *Logger) DebugAtPos(pos string, a ...any) error {
+	return nil
+}
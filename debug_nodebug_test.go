@@ -0,0 +1,24 @@
+//go:build nodebug
+
+package logs
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestDebugMethodsAreNoops(t *testing.T) {
+	logger, buf := newTestLogger(t, LogLevelDebug)
+	var hooked int
+	logger.AddHook(func(*Entry) { hooked++ })
+	_ = logger.Debug(0, "a", 1)
+	_ = logger.Debugf(0, "b %d", 2)
+	_ = logger.DebugError(0, errors.New("c"))
+	_ = logger.DebugCallers(0, 1, "d", 4)
+	_ = logger.DebugContext(context.Background(), 0, "e", 5)
+	_ = logger.DebugAtPos("config.yaml:1", "f", 6)
+	if buf.Len() != 0 || hooked != 0 {
+		t.Fatalf("debug methods should be no-ops under nodebug: %q", buf.String())
+	}
+}
@@ -0,0 +1,78 @@
+//go:build !nodebug
+
+package logs
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestDebugMethods(t *testing.T) {
+	logger, buf := newTestLogger(t, LogLevelDebug)
+	_ = logger.Debug(0, "a", 1)
+	_ = logger.Debugf(0, "b %d", 2)
+	_ = logger.DebugError(0, errors.New("c"))
+	_ = logger.DebugCallers(0, 1, "d", 4)
+	_ = logger.DebugContext(context.Background(), 0, "e", 5)
+	_ = logger.DebugAtPos("config.yaml:1", "f", 6)
+
+	out := buf.String()
+	if n := strings.Count(out, "DEBUG"); n != 6 {
+		t.Fatalf("want 6 debug records, got %d: %q", n, out)
+	}
+	for _, want := range []string{"a=1", "msg=b 2", "error=c", "d=4", "e=5", "| config.yaml:1 |"} {
+		if !strings.Contains(out, want) {
+			t.Fatalf("missing %q in %q", want, out)
+		}
+	}
+	if !strings.Contains(out, "debug_test.go:") {
+		t.Fatalf("position should point to the caller: %q", out)
+	}
+}
+
+func TestSetEnabledLevels(t *testing.T) {
+	logger, buf := newTestLogger(t, LogLevelInfo)
+	logger.SetEnabledLevels(LogLevelDebug, LogLevelError)
+	_ = logger.Debug(0, "a", 1)
+	_ = logger.Info(0, "a", 1)
+	_ = logger.Warn(0, "a", 1)
+	_ = logger.WarnError(0, Errorf("x"))
+	_ = logger.Error(0, "a", 1)
+	_ = logger.Keyword(0, "a", 1)
+
+	out := buf.String()
+	if strings.Count(out, "\n") != 2 || !strings.Contains(out, "DEBUG") || !strings.Contains(out, "ERROR") {
+		t.Fatalf("only debug and error should be emitted: %q", out)
+	}
+
+	// 不传参数恢复使用阈值
+	buf.Reset()
+	logger.SetEnabledLevels()
+	_ = logger.Debug(0, "a", 1)
+	_ = logger.Warn(0, "a", 1)
+	if out = buf.String(); strings.Contains(out, "DEBUG") || !strings.Contains(out, "WARN") {
+		t.Fatalf("threshold should apply again: %q", out)
+	}
+}
+
+func TestArgs(t *testing.T) {
+	logger, buf := newTestLogger(t, LogLevelDebug)
+	_ = logger.Debug(0, "call", Args("id", 5, "name", "x"), "top", 1)
+	if !strings.HasSuffix(buf.String(), "| call={id=5 name=x} top=1\n") {
+		t.Fatalf("args should be nested in text: %q", buf.String())
+	}
+
+	buf.Reset()
+	logger.SetFormatter(&JSONFormatter{})
+	_ = logger.Debug(0, "call", Args("id", 5, Field{Key: "name", Value: "x"}))
+	m := decodeJSON(t, strings.TrimSpace(buf.String()))
+	call, ok := m["call"].(map[string]any)
+	if !ok || call["id"] != float64(5) || call["name"] != "x" {
+		t.Fatalf("args should be a nested json object, got %v", m["call"])
+	}
+	if _, ok := m["id"]; ok {
+		t.Fatalf("args should not be flattened into top-level keys: %s", buf.String())
+	}
+}
@@ -0,0 +1,17 @@
+package logs
+
+import "io"
+
+// DeferClose 关闭c并以Error等级输出关闭时的异常，用于defer
+func DeferClose(logger *Logger, c io.Closer) {
+	if err := c.Close(); err != nil {
+		_ = logger.ErrorError(1, err)
+	}
+}
+
+// DeferErr 调用fn并以Error等级输出其返回的异常，用于defer
+func DeferErr(logger *Logger, fn func() error) {
+	if err := fn(); err != nil {
+		_ = logger.ErrorError(1, err)
+	}
+}
@@ -0,0 +1,46 @@
+package logs
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+// 关闭时返回异常
+type failingCloser struct{}
+
+func (failingCloser) Close() error { return errors.New("close failed") }
+
+// 正常关闭
+type okCloser struct{}
+
+func (okCloser) Close() error { return nil }
+
+func TestDeferClose(t *testing.T) {
+	logger, buf := newTestLogger(t, LogLevelDebug)
+	func() {
+		defer DeferClose(logger, okCloser{})
+	}()
+	if buf.Len() != 0 {
+		t.Fatalf("successful close should not log: %q", buf.String())
+	}
+
+	func() {
+		defer DeferClose(logger, failingCloser{})
+	}()
+	out := buf.String()
+	if !strings.Contains(out, "defer_test.go:") || !strings.Contains(out, "error=close failed") {
+		t.Fatalf("close error should be logged at the caller: %q", out)
+	}
+}
+
+func TestDeferErr(t *testing.T) {
+	logger, buf := newTestLogger(t, LogLevelDebug)
+	func() {
+		defer DeferErr(logger, func() error { return errors.New("flush failed") })
+	}()
+	out := buf.String()
+	if !strings.Contains(out, "defer_test.go:") || !strings.Contains(out, "error=flush failed") {
+		t.Fatalf("error should be logged at the caller: %q", out)
+	}
+}
@@ -0,0 +1,47 @@
+package logs
+
+import (
+	"bytes"
+	"fmt"
+	"reflect"
+	"strconv"
+)
+
+// 变更前后的值
+type diffValue struct {
+	before, after any
+}
+
+// Diff 记录变更前后值的字段，文本格式为`"a"→"b"`，json格式为{"old":"a","new":"b","changed":true}
+func Diff(key string, before, after any) Field {
+	return Field{Key: key, Value: diffValue{before: before, after: after}}
+}
+
+func (self diffValue) changed() bool {
+	return !reflect.DeepEqual(self.before, self.after)
+}
+
+func (self diffValue) String() string {
+	if !self.changed() {
+		return diffString(self.before) + " (unchanged)"
+	}
+	return diffString(self.before) + "→" + diffString(self.after)
+}
+
+func (self diffValue) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	err := writeJSONObject(&buf, []Field{
+		{Key: "old", Value: self.before},
+		{Key: "new", Value: self.after},
+		{Key: "changed", Value: self.changed()},
+	})
+	return buf.Bytes(), err
+}
+
+// 格式化变更值，字符串加引号以区分空值
+func diffString(v any) string {
+	if s, ok := v.(string); ok {
+		return strconv.Quote(s)
+	}
+	return fmt.Sprintf("%v", v)
+}
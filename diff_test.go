@@ -0,0 +1,36 @@
+package logs
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestDiff(t *testing.T) {
+	logger, buf := newTestLogger(t, LogLevelDebug)
+	_ = logger.Info(0, Diff("name", "a", "b"), Diff("age", 1, 1))
+	if want := `name="a"→"b" age=1 (unchanged)`; !strings.Contains(buf.String(), want) {
+		t.Fatalf("want %q in text output %q", want, buf.String())
+	}
+
+	buf.Reset()
+	logger.SetFormatter(&JSONFormatter{})
+	_ = logger.Info(0, Diff("name", "a", "b"), Diff("age", 1, 1))
+	m := decodeJSON(t, strings.TrimSpace(buf.String()))
+	name, _ := m["name"].(map[string]any)
+	if name["old"] != "a" || name["new"] != "b" || name["changed"] != true {
+		t.Fatalf("unexpected changed diff: %v", m["name"])
+	}
+	age, _ := m["age"].(map[string]any)
+	if age["old"] != float64(1) || age["new"] != float64(1) || age["changed"] != false {
+		t.Fatalf("unexpected unchanged diff: %v", m["age"])
+	}
+}
+
+func TestDiffDeepEqual(t *testing.T) {
+	if (diffValue{before: []int{1, 2}, after: []int{1, 2}}).changed() {
+		t.Fatal("equal slices should be unchanged")
+	}
+	if got := (diffValue{before: "", after: "x"}).String(); got != `""→"x"` {
+		t.Fatalf("empty string should be quoted, got %q", got)
+	}
+}
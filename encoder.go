@@ -0,0 +1,36 @@
+package logs
+
+import (
+	"reflect"
+	"sync"
+)
+
+// Encoder 字段值编码器，返回文本格式中的字符串形式及json格式中用于序列化的值
+type Encoder func(v any) (text string, json any)
+
+var (
+	encoderLock sync.RWMutex
+	encoders    = make(map[reflect.Type]Encoder)
+)
+
+// RegisterEncoder 注册指定类型字段值的编码器，文本及json格式化时优先使用，encoder为nil时取消注册
+func RegisterEncoder(typ reflect.Type, encoder Encoder) {
+	encoderLock.Lock()
+	defer encoderLock.Unlock()
+	if encoder == nil {
+		delete(encoders, typ)
+	} else {
+		encoders[typ] = encoder
+	}
+}
+
+// 获取字段值类型注册的编码器
+func lookupEncoder(v any) (Encoder, bool) {
+	encoderLock.RLock()
+	defer encoderLock.RUnlock()
+	if len(encoders) == 0 {
+		return nil, false
+	}
+	encoder, ok := encoders[reflect.TypeOf(v)]
+	return encoder, ok
+}
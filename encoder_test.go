@@ -0,0 +1,74 @@
+package logs
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// 带自定义编码的金额
+type money struct {
+	cents int64
+}
+
+// 带自定义编码的异常
+type codedError struct {
+	code int
+}
+
+func (self codedError) Error() string { return "coded" }
+
+func TestRegisterEncoder(t *testing.T) {
+	RegisterEncoder(reflect.TypeOf(money{}), func(v any) (string, any) {
+		return "$1.50", map[string]any{"cents": v.(money).cents, "currency": "USD"}
+	})
+	defer RegisterEncoder(reflect.TypeOf(money{}), nil)
+
+	logger, buf := newTestLogger(t, LogLevelDebug)
+	_ = logger.Info(0, "price", money{150})
+	if !strings.HasSuffix(buf.String(), "| price=$1.50\n") {
+		t.Fatalf("text should use the encoder: %q", buf.String())
+	}
+
+	buf.Reset()
+	logger.SetFormatter(&JSONFormatter{})
+	_ = logger.Info(0, "price", money{150})
+	price, _ := decodeJSON(t, strings.TrimSpace(buf.String()))["price"].(map[string]any)
+	if price["cents"] != float64(150) || price["currency"] != "USD" {
+		t.Fatalf("json should use the encoder: %s", buf.String())
+	}
+}
+
+func TestRegisterEncoderForErrorType(t *testing.T) {
+	RegisterEncoder(reflect.TypeOf(codedError{}), func(v any) (string, any) {
+		return "E42", []Field{{Key: "code", Value: v.(codedError).code}}
+	})
+	defer RegisterEncoder(reflect.TypeOf(codedError{}), nil)
+
+	logger, buf := newTestLogger(t, LogLevelDebug)
+	_ = logger.Info(0, "err", codedError{code: 42})
+	if !strings.HasSuffix(buf.String(), "| err=E42\n") {
+		t.Fatalf("text should use the encoder over Error(): %q", buf.String())
+	}
+
+	buf.Reset()
+	logger.SetFormatter(&JSONFormatter{})
+	_ = logger.Info(0, "err", codedError{code: 42})
+	errField, ok := decodeJSON(t, strings.TrimSpace(buf.String()))["err"].(map[string]any)
+	if !ok || errField["code"] != float64(42) {
+		t.Fatalf("json should use the encoder over Error(): %s", buf.String())
+	}
+}
+
+func TestRegisterEncoderSameType(t *testing.T) {
+	// 编码结果与原值同类型时按原值处理
+	RegisterEncoder(reflect.TypeOf(codedError{}), func(v any) (string, any) { return "E", v })
+	defer RegisterEncoder(reflect.TypeOf(codedError{}), nil)
+
+	logger, buf := newTestLogger(t, LogLevelDebug)
+	logger.SetFormatter(&JSONFormatter{})
+	_ = logger.Info(0, "err", codedError{code: 1})
+	if m := decodeJSON(t, strings.TrimSpace(buf.String())); m["err"] != "coded" {
+		t.Fatalf("same-type result should fall back to Error(), got %v", m["err"])
+	}
+}
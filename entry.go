@@ -0,0 +1,185 @@
+package logs
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+
+	"github.com/kkkunny/containers/linkedhashmap"
+)
+
+// Field 日志字段，可直接作为item传入日志方法，值为[]Field时表示嵌套对象
+type Field struct {
+	Key   string
+	Value any
+}
+
+// Args 将函数参数作为嵌套对象，pairs为Field或键值对，如logger.Debug(0, "call", logs.Args("id", 5, "name", "x"))
+func Args(pairs ...any) []Field {
+	fields := make([]Field, 0, len(pairs)/2)
+	rangeItems(pairs, func(key string, value any) {
+		fields = append(fields, Field{Key: key, Value: value})
+	})
+	return fields
+}
+
+// Entry 日志记录，输出完成后会被回收复用，处理器、钩子及格式化器不能在调用结束后继续持有记录或其字段切片，需要保留时应复制
+type Entry struct {
+	Level   LogLevel  // 日志等级
+	Time    time.Time // 记录时间
+	Caller  string    // 调用位置
+	Globals []Field   // 日志管理器的全局字段
+	Fields  []Field   // 本条记录的字段
+}
+
+// Clone 复制日志记录，用于在处理器或钩子调用结束后保留记录
+func (self *Entry) Clone() *Entry {
+	entry := *self
+	entry.Globals = append([]Field(nil), self.Globals...)
+	entry.Fields = append([]Field(nil), self.Fields...)
+	return &entry
+}
+
+// 日志记录池
+var entryPool = sync.Pool{
+	New: func() any {
+		return new(Entry)
+	},
+}
+
+// 从池中获取日志记录
+func getEntry() *Entry {
+	return entryPool.Get().(*Entry)
+}
+
+// 重置日志记录并放回池中
+func putEntry(entry *Entry) {
+	for i := range entry.Globals {
+		entry.Globals[i] = Field{}
+	}
+	for i := range entry.Fields {
+		entry.Fields[i] = Field{}
+	}
+	*entry = Entry{
+		Globals: entry.Globals[:0],
+		Fields:  entry.Fields[:0],
+	}
+	entryPool.Put(entry)
+}
+
+// 将有序字典中的字段追加到字段列表
+func appendFields(fields []Field, values *linkedhashmap.LinkedHashMap[string, any]) []Field {
+	for iter := values.Begin(); iter != nil; iter.Next() {
+		fields = append(fields, Field{Key: iter.Key(), Value: iter.Value()})
+		if !iter.HasNext() {
+			break
+		}
+	}
+	return fields
+}
+
+// 格式化字段值，优先使用注册的编码器
+func formatValue(v any) string {
+	switch v := v.(type) {
+	case string:
+		return v
+	case []Field:
+		var buf strings.Builder
+		buf.WriteByte('{')
+		for i, field := range v {
+			if i > 0 {
+				buf.WriteByte(' ')
+			}
+			buf.WriteString(field.Key)
+			buf.WriteByte('=')
+			buf.WriteString(formatValue(field.Value))
+		}
+		buf.WriteByte('}')
+		return buf.String()
+	default:
+		if encoder, ok := lookupEncoder(v); ok {
+			text, _ := encoder(v)
+			return text
+		}
+		if rv := reflect.ValueOf(v); rv.Kind() == reflect.Map {
+			return formatValue(mapToSortedFields(rv))
+		}
+		return fmt.Sprintf("%v", v)
+	}
+}
+
+// 文本格式下字段值是否需要加引号
+func needQuote(s string) bool {
+	if s == "" {
+		return true
+	}
+	for _, r := range s {
+		if r == '=' || r == '"' || unicode.IsSpace(r) || !unicode.IsPrint(r) {
+			return true
+		}
+	}
+	return false
+}
+
+// 将map转换为按键排序的字段列表，保证输出稳定；数值、字符串及布尔类型的键按值排序，其余按%v的结果排序
+func mapToSortedFields(m reflect.Value) []Field {
+	keys := m.MapKeys()
+	sort.Slice(keys, func(i, j int) bool {
+		return lessMapKey(keys[i], keys[j])
+	})
+	fields := make([]Field, len(keys))
+	for i, key := range keys {
+		fields[i] = Field{Key: fmt.Sprintf("%v", key.Interface()), Value: m.MapIndex(key).Interface()}
+	}
+	return fields
+}
+
+// map键的排序分类，不同分类的键按分类排序
+func mapKeyRank(k reflect.Kind) int {
+	switch k {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return 0
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return 1
+	case reflect.Float32, reflect.Float64:
+		return 2
+	case reflect.String:
+		return 3
+	case reflect.Bool:
+		return 4
+	default:
+		return 5
+	}
+}
+
+// 比较map的两个键
+func lessMapKey(a, b reflect.Value) bool {
+	if a.Kind() == reflect.Interface {
+		a = a.Elem()
+	}
+	if b.Kind() == reflect.Interface {
+		b = b.Elem()
+	}
+	rankA, rankB := mapKeyRank(a.Kind()), mapKeyRank(b.Kind())
+	if rankA != rankB {
+		return rankA < rankB
+	}
+	switch rankA {
+	case 0:
+		return a.Int() < b.Int()
+	case 1:
+		return a.Uint() < b.Uint()
+	case 2:
+		return a.Float() < b.Float()
+	case 3:
+		return a.String() < b.String()
+	case 4:
+		return !a.Bool() && b.Bool()
+	default:
+		return fmt.Sprintf("%v", a) < fmt.Sprintf("%v", b)
+	}
+}
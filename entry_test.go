@@ -0,0 +1,136 @@
+package logs
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestMapValueStableOrder(t *testing.T) {
+	logger, _ := newTestLogger(t, LogLevelInfo)
+	m := map[string]any{"b": 2, "a": 1, "c": map[int]int{3: 1, 1: 2}, "d": 4, "e": 5}
+	entry := &Entry{Level: LogLevelInfo, Time: time.Unix(0, 0), Fields: []Field{{Key: "m", Value: m}}}
+
+	first, err := logger.Render(entry)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 20; i++ {
+		if s, _ := logger.Render(entry); s != first {
+			t.Fatalf("unstable output: %q vs %q", first, s)
+		}
+	}
+	if !strings.HasSuffix(first, "m={a=1 b=2 c={1=2 3=1} d=4 e=5}") {
+		t.Fatalf("map keys should be sorted: %q", first)
+	}
+
+	logger.SetFormatter(&JSONFormatter{})
+	s, err := logger.Render(entry)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(s, `"m":{"a":1,"b":2,"c":{"1":2,"3":1},"d":4,"e":5}`) {
+		t.Fatalf("json map keys should be sorted: %q", s)
+	}
+}
+
+func TestMapValueTypedKeyOrder(t *testing.T) {
+	logger, _ := newTestLogger(t, LogLevelInfo)
+	entry := &Entry{Level: LogLevelInfo, Time: time.Unix(0, 0), Fields: []Field{
+		{Key: "n", Value: map[int]string{100: "c", 9: "a", 10: "b"}},
+		{Key: "f", Value: map[float64]int{2.5: 1, -1: 2}},
+		{Key: "x", Value: map[any]int{"b": 1, 10: 2, 9: 3}},
+	}}
+	s, err := logger.Render(entry)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// 数值键按数值排序，不同类型的键按分类排序
+	if !strings.HasSuffix(s, "n={9=a 10=b 100=c} f={-1=2 2.5=1} x={9=3 10=2 b=1}") {
+		t.Fatalf("map keys should be sorted by typed value: %q", s)
+	}
+
+	logger.SetFormatter(&JSONFormatter{})
+	if s, err = logger.Render(entry); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(s, `"n":{"9":"a","10":"b","100":"c"}`) {
+		t.Fatalf("json map keys should be sorted by typed value: %q", s)
+	}
+}
+
+func TestNilMapValueJSON(t *testing.T) {
+	logger, buf := newTestLogger(t, LogLevelInfo)
+	logger.SetFormatter(&JSONFormatter{})
+	var m map[string]int
+	_ = logger.Info(0, "m", m)
+	if !strings.Contains(buf.String(), `"m":null`) {
+		t.Fatalf("nil map should be null: %q", buf.String())
+	}
+}
+
+// 按行记录写入内容的并发安全写入器
+type lineRecorder struct {
+	lock  sync.Mutex
+	lines []string
+}
+
+func (self *lineRecorder) Write(p []byte) (int, error) {
+	self.lock.Lock()
+	defer self.lock.Unlock()
+	self.lines = append(self.lines, string(p))
+	return len(p), nil
+}
+
+func TestPooledEntriesUnderConcurrency(t *testing.T) {
+	w := &lineRecorder{}
+	logger := NewLogger(LogLevelDebug, w, "app", "x")
+	logger.SetFormatter(&JSONFormatter{})
+	var hookErr atomic.Value
+	logger.AddHook(func(entry *Entry) {
+		// 钩子中看到的记录应与本次输出一致
+		g, i := entry.Fields[0].Value, entry.Fields[1].Value
+		if entry.Fields[2].Value != fmt.Sprint(g, "-", i) {
+			hookErr.Store(fmt.Sprintf("corrupted entry in hook: %v", entry.Fields))
+		}
+	})
+
+	var wg sync.WaitGroup
+	for g := 0; g < 8; g++ {
+		wg.Add(1)
+		go func(g int) {
+			defer wg.Done()
+			for i := 0; i < 200; i++ {
+				_ = logger.Info(0, "g", g, "i", i, "s", fmt.Sprint(g, "-", i))
+			}
+		}(g)
+	}
+	wg.Wait()
+
+	if err := hookErr.Load(); err != nil {
+		t.Fatal(err)
+	}
+	if len(w.lines) != 8*200 {
+		t.Fatalf("want %d records, got %d", 8*200, len(w.lines))
+	}
+	for _, line := range w.lines {
+		m := decodeJSON(t, line)
+		if m["app"] != "x" || m["s"] != fmt.Sprint(m["g"], "-", m["i"]) {
+			t.Fatalf("corrupted record: %q", line)
+		}
+	}
+}
+
+func TestEntryPoolReuse(t *testing.T) {
+	allocs := testing.AllocsPerRun(100, func() {
+		entry := getEntry()
+		entry.Fields = append(entry.Fields, Field{Key: "a", Value: "b"})
+		putEntry(entry)
+	})
+	if allocs > 0 {
+		t.Fatalf("pooled entries should not allocate, got %v allocs per record", allocs)
+	}
+}
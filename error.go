@@ -4,6 +4,7 @@ import (
 	"errors"
 	"fmt"
 	"runtime"
+	"strings"
 )
 
 // Error 异常
@@ -16,14 +17,29 @@ type Error interface {
 
 type logError struct {
 	stacks []runtime.Frame
-	err   error
+	msg    string
+	err    error
 }
 
+// StackCapturer 获取栈帧信息，skip为0表示调用方，返回的栈帧由外到内排列（最后一个为出错位置），可替换以定制捕获方式
+var StackCapturer = captureStack
+
 func newLogError(skip uint, err error) *logError {
+	return &logError{
+		stacks: StackCapturer(skip + 1),
+		err:    err,
+	}
+}
+
+// 默认的栈帧捕获方式
+func captureStack(skip uint) []runtime.Frame {
 	var reverseStacks []runtime.Frame
 	pcs := make([]uintptr, 20)
 
 	n := runtime.Callers(int(skip)+2, pcs)
+	if n == 0 {
+		return nil
+	}
 	frames := runtime.CallersFrames(pcs[:n-1])
 	for frame, exist := frames.Next(); exist; frame, exist = frames.Next() {
 		if !exist {
@@ -33,14 +49,10 @@ func newLogError(skip uint, err error) *logError {
 	}
 
 	stacks := make([]runtime.Frame, len(reverseStacks))
-	for i, s := range reverseStacks{
+	for i, s := range reverseStacks {
 		stacks[len(reverseStacks)-i-1] = s
 	}
-
-	return &logError{
-		stacks: stacks,
-		err: err,
-	}
+	return stacks
 }
 
 // ErrorWrap 包装异常
@@ -67,12 +79,98 @@ func ErrorWith[T any](v T, err error) (T, Error) {
 	return v, newLogError(1, err)
 }
 
+// ErrorWrapf 包装异常并附加信息，若err已带栈则沿用其栈帧信息
+func ErrorWrapf(err error, f string, a ...any) Error {
+	if err == nil {
+		return nil
+	}
+	var stacks []runtime.Frame
+	var logErr Error
+	if errors.As(err, &logErr) {
+		stacks = logErr.Stacks()
+	} else {
+		stacks = newLogError(1, err).stacks
+	}
+	return &logError{
+		stacks: stacks,
+		msg:    fmt.Sprintf(f, a...),
+		err:    err,
+	}
+}
+
+// StackDepth 获取异常链中带栈异常捕获的栈帧数，不存在带栈异常时返回0
+func StackDepth(err error) int {
+	var logErr Error
+	if !errors.As(err, &logErr) {
+		return 0
+	}
+	return len(logErr.Stacks())
+}
+
+// FromRecover 将recover()得到的值转换为带栈异常，栈帧截取到发生panic的位置，r为nil时返回nil
+func FromRecover(r any) Error {
+	if r == nil {
+		return nil
+	}
+	err, ok := r.(error)
+	if !ok {
+		err = fmt.Errorf("%v", r)
+	}
+
+	stacks := StackCapturer(1)
+	for i := len(stacks) - 1; i >= 0; i-- {
+		if stacks[i].Function != "runtime.gopanic" {
+			continue
+		}
+		// 去掉panic之后的处理函数及运行时内部的栈帧
+		stacks = stacks[:i]
+		for len(stacks) > 0 && strings.HasPrefix(stacks[len(stacks)-1].Function, "runtime.") {
+			stacks = stacks[:len(stacks)-1]
+		}
+		break
+	}
+	return &logError{
+		stacks: stacks,
+		msg:    "panic",
+		err:    err,
+	}
+}
+
+// FormatError 将异常格式化为便于阅读的多行文本，依次为异常信息、包装链中各层的异常信息及栈帧信息，err为nil时返回空字符串
+func FormatError(err error) string {
+	if err == nil {
+		return ""
+	}
+
+	var buf strings.Builder
+	msg := err.Error()
+	buf.WriteString(msg)
+	for e := errors.Unwrap(err); e != nil; e = errors.Unwrap(e) {
+		// 不带附加信息的包装层与内层信息相同，不重复输出
+		if m := e.Error(); m != msg {
+			msg = m
+			buf.WriteString("\n  caused by: ")
+			buf.WriteString(msg)
+		}
+	}
+
+	var logErr Error
+	if errors.As(err, &logErr) && len(logErr.Stacks()) > 0 {
+		buf.WriteString("\nstack:")
+		buf.WriteString(formatStack(logErr.Stacks(), 0))
+	}
+	return buf.String()
+}
+
 // Errorf 新建异常
 func Errorf(f string, a ...any) Error {
 	return newLogError(1, fmt.Errorf(f, a...))
 }
 
 func (self *logError) Error() string {
+	if self.msg != "" {
+		return self.msg + ": " + self.err.Error()
+	}
 	return self.err.Error()
 }
 
@@ -81,8 +179,11 @@ func (self *logError) Stacks() []runtime.Frame {
 	return self.stacks
 }
 
-// Stack 获取栈帧信息
+// Stack 获取栈帧信息，没有栈帧信息时返回零值
 func (self *logError) Stack() runtime.Frame {
+	if len(self.stacks) == 0 {
+		return runtime.Frame{}
+	}
 	return self.stacks[len(self.stacks)-1]
 }
 
@@ -0,0 +1,239 @@
+package logs
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+// 自行实现的带栈异常
+type customError struct {
+	stacks []runtime.Frame
+}
+
+func (self customError) Error() string { return "custom" }
+
+func (self customError) Stack() runtime.Frame {
+	if len(self.stacks) == 0 {
+		return runtime.Frame{}
+	}
+	return self.stacks[len(self.stacks)-1]
+}
+
+func (self customError) Stacks() []runtime.Frame { return self.stacks }
+
+func (self customError) Unwrap() error { return nil }
+
+func TestCustomErrorWithStacks(t *testing.T) {
+	logger, buf := newTestLogger(t, LogLevelDebug)
+	err := customError{stacks: []runtime.Frame{{File: "main.go", Line: 1}, {File: "a.go", Line: 3}}}
+	if e := logger.ErrorError(0, err); e != nil {
+		t.Fatal(e)
+	}
+	out := buf.String()
+	if !strings.Contains(out, "| a.go:3 |") {
+		t.Fatalf("caller should be the innermost frame: %q", out)
+	}
+	if !strings.Contains(out, "error=custom stack=\n\tmain.go:1\n\ta.go:3") {
+		t.Fatalf("missing stack: %q", out)
+	}
+}
+
+func TestCustomErrorWithoutStacks(t *testing.T) {
+	logger, buf := newTestLogger(t, LogLevelDebug)
+	if e := logger.ErrorError(0, customError{}); e != nil {
+		t.Fatal(e)
+	}
+	out := buf.String()
+	if !strings.Contains(out, "error_test.go:") || !strings.Contains(out, "error=custom") {
+		t.Fatalf("should fall back to a plain error at the call site: %q", out)
+	}
+	if strings.Contains(out, "stack=") {
+		t.Fatalf("unexpected stack: %q", out)
+	}
+}
+
+func TestLogErrorStackWithoutFrames(t *testing.T) {
+	if frame := (&logError{}).Stack(); frame != (runtime.Frame{}) {
+		t.Fatalf("want zero frame, got %+v", frame)
+	}
+}
+
+func TestErrorWrapfBreadcrumbs(t *testing.T) {
+	logger, buf := newTestLogger(t, LogLevelDebug)
+	err := ErrorWrapf(ErrorWrapf(ErrorWrap(errors.New("inner")), "middle"), "outer")
+	_ = logger.ErrorError(0, err)
+	out := buf.String()
+	if !strings.Contains(out, "error=outer: middle: inner") {
+		t.Fatalf("missing breadcrumbs: %q", out)
+	}
+	if n := strings.Count(out, "stack="); n != 1 {
+		t.Fatalf("want a single stack, got %d: %q", n, out)
+	}
+
+	// 外层普通包装的信息同样保留
+	buf.Reset()
+	_ = logger.ErrorError(0, fmt.Errorf("ctx: %w", err))
+	if !strings.Contains(buf.String(), "error=ctx: outer: middle: inner") {
+		t.Fatalf("missing outer message: %q", buf.String())
+	}
+}
+
+func TestMaxStackFrames(t *testing.T) {
+	logger, buf := newTestLogger(t, LogLevelDebug)
+	logger.SetMaxStackFrames(2)
+	err := customError{stacks: []runtime.Frame{{File: "a.go", Line: 1}, {File: "b.go", Line: 2}, {File: "c.go", Line: 3}}}
+	_ = logger.ErrorError(0, err)
+	out := buf.String()
+	if strings.Contains(out, "a.go:1") {
+		t.Fatalf("frames beyond the cap should be dropped: %q", out)
+	}
+	if !strings.Contains(out, "stack=\n\tb.go:2\n\tc.go:3") {
+		t.Fatalf("frames closest to the error should be kept: %q", out)
+	}
+	// 上限不影响捕获的栈帧数
+	if depth := StackDepth(err); depth != 3 {
+		t.Fatalf("want StackDepth 3, got %d", depth)
+	}
+}
+
+func TestStackDepth(t *testing.T) {
+	if depth := StackDepth(errors.New("plain")); depth != 0 {
+		t.Fatalf("plain error should have depth 0, got %d", depth)
+	}
+	err := fmt.Errorf("wrap: %w", Errorf("inner"))
+	if depth := StackDepth(err); depth == 0 {
+		t.Fatal("wrapped stack error should report its captured frames")
+	}
+}
+
+func TestSetErrorKeys(t *testing.T) {
+	logger, buf := newTestLogger(t, LogLevelDebug)
+	logger.SetErrorKeys("err", "err.stack")
+	_ = logger.ErrorError(0, errors.New("a"))
+	if !strings.HasSuffix(buf.String(), "| err=a\n") {
+		t.Fatalf("plain error should use the custom key: %q", buf.String())
+	}
+
+	// 子日志管理器继承字段名
+	buf.Reset()
+	_ = logger.NewGroup().ErrorError(0, Errorf("b"))
+	if !strings.Contains(buf.String(), "err=b err.stack=\n\t") {
+		t.Fatalf("stack error should use the custom keys: %q", buf.String())
+	}
+	if strings.Contains(buf.String(), "error=") || strings.Contains(buf.String(), " stack=") {
+		t.Fatalf("default keys should not appear: %q", buf.String())
+	}
+}
+
+func TestDefaultStackCapturer(t *testing.T) {
+	_, file, line, _ := runtime.Caller(0)
+	err := Errorf("x")
+	if stack := err.Stack(); stack.File != file || stack.Line != line+1 {
+		t.Fatalf("innermost frame should be the Errorf call at %s:%d, got %s:%d", file, line+1, stack.File, stack.Line)
+	}
+}
+
+func TestStackCapturer(t *testing.T) {
+	defer func(fn func(uint) []runtime.Frame) { StackCapturer = fn }(StackCapturer)
+	var gotSkip uint
+	StackCapturer = func(skip uint) []runtime.Frame {
+		gotSkip = skip
+		return []runtime.Frame{{File: "main.go", Line: 1}, {File: "fake.go", Line: 2}}
+	}
+
+	err := Errorf("x")
+	if stack := err.Stack(); stack.File != "fake.go" || stack.Line != 2 {
+		t.Fatalf("Errorf should use the replaced capturer, got %s:%d", stack.File, stack.Line)
+	}
+	if gotSkip == 0 {
+		t.Fatal("capturer should be asked to skip the package's own frames")
+	}
+
+	logger, buf := newTestLogger(t, LogLevelDebug)
+	_ = logger.ErrorError(0, ErrorWrapf(errors.New("inner"), "outer"))
+	if !strings.Contains(buf.String(), "\tmain.go:1\n\tfake.go:2") {
+		t.Fatalf("rendered stack should come from the replaced capturer: %q", buf.String())
+	}
+}
+
+func TestShowErrorType(t *testing.T) {
+	logger, buf := newTestLogger(t, LogLevelDebug)
+	_, err := os.Open(filepath.Join(t.TempDir(), "missing"))
+	_ = logger.ErrorError(0, err)
+	if strings.Contains(buf.String(), "error.type=") {
+		t.Fatalf("error type should be off by default: %q", buf.String())
+	}
+
+	logger.SetShowErrorType(true)
+	buf.Reset()
+	_ = logger.ErrorError(0, fmt.Errorf("ctx: %w", err))
+	if want := "error.type=*fmt.wrapError > *fs.PathError > syscall.Errno"; !strings.Contains(buf.String(), want) {
+		t.Fatalf("want %q in %q", want, buf.String())
+	}
+
+	// 本包的包装类型不计入
+	buf.Reset()
+	_ = logger.ErrorError(0, ErrorWrapf(err, "open"))
+	if want := "error.type=*fs.PathError > syscall.Errno stack="; !strings.Contains(buf.String(), want) {
+		t.Fatalf("want %q in %q", want, buf.String())
+	}
+}
+
+func TestAlwaysCaptureStack(t *testing.T) {
+	logger, buf := newTestLogger(t, LogLevelDebug)
+	logger.AlwaysCaptureStack(LogLevelWarn)
+	_ = logger.Info(0, "msg", "i")
+	if strings.Contains(buf.String(), "stack=") {
+		t.Fatalf("records below the level should not carry a stack: %q", buf.String())
+	}
+
+	for _, log := range []func() (string, int){
+		func() (string, int) {
+			_, file, line, _ := runtime.Caller(0)
+			_ = logger.Warn(0, "msg", "w")
+			return file, line + 1
+		},
+		func() (string, int) {
+			_, file, line, _ := runtime.Caller(0)
+			_ = logger.Warnf(0, "x %d", 1)
+			return file, line + 1
+		},
+	} {
+		buf.Reset()
+		file, line := log()
+		out := strings.TrimSuffix(buf.String(), "\n")
+		if !strings.Contains(out, "stack=\n\t") {
+			t.Fatalf("warn record should carry a stack: %q", out)
+		}
+		// 最内层栈帧为调用处
+		lines := strings.Split(out, "\n")
+		if want := fmt.Sprintf("\t%s:%d", file, line); lines[len(lines)-1] != want {
+			t.Fatalf("innermost frame should be %q, got %q", want, lines[len(lines)-1])
+		}
+	}
+}
+
+func TestFormatError(t *testing.T) {
+	_, file, line, _ := runtime.Caller(0)
+	err := ErrorWrapf(fmt.Errorf("write: %w", errors.New("disk full")), "save %s", "a.txt")
+	s := FormatError(err)
+	want := "save a.txt: write: disk full\n  caused by: write: disk full\n  caused by: disk full\nstack:\n\t"
+	if !strings.HasPrefix(s, want) {
+		t.Fatalf("want prefix %q, got %q", want, s)
+	}
+	if frame := fmt.Sprintf("\t%s:%d", file, line+1); !strings.HasSuffix(s, frame) {
+		t.Fatalf("stack should end at the wrap site %q: %q", frame, s)
+	}
+
+	if s := FormatError(errors.New("plain")); s != "plain" {
+		t.Fatalf("plain error should render only its message, got %q", s)
+	}
+	if s := FormatError(nil); s != "" {
+		t.Fatalf("nil error should render empty, got %q", s)
+	}
+}
@@ -0,0 +1,11 @@
+package logs
+
+import "os"
+
+var osExit = os.Exit
+
+// Exit 刷新默认日志管理器后退出程序
+func Exit(code int) {
+	_ = Default().Flush()
+	osExit(code)
+}
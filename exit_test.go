@@ -0,0 +1,36 @@
+package logs
+
+import (
+	"bytes"
+	"testing"
+)
+
+// 记录刷新次数的写入器
+type flushRecorder struct {
+	bytes.Buffer
+	flushed int
+}
+
+func (self *flushRecorder) Flush() error {
+	self.flushed++
+	return nil
+}
+
+func TestExitFlushesBeforeExit(t *testing.T) {
+	w := &flushRecorder{}
+	defer SetDefault(Default())
+	SetDefault(NewLogger(LogLevelInfo, w))
+	defer func(exit func(int)) { osExit = exit }(osExit)
+
+	var exitCode, flushedAtExit int
+	osExit = func(code int) {
+		exitCode, flushedAtExit = code, w.flushed
+	}
+	Exit(3)
+	if exitCode != 3 {
+		t.Fatalf("want exit code 3, got %d", exitCode)
+	}
+	if flushedAtExit != 1 {
+		t.Fatalf("default logger should be flushed once before exit, got %d", flushedAtExit)
+	}
+}
@@ -0,0 +1,175 @@
+package logs
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Formatter 格式化器，将日志记录格式化为一行文本（不含换行符），调用结束后不能继续持有记录
+type Formatter interface {
+	Format(entry *Entry) (string, error)
+}
+
+// TimeMode 时间输出格式
+type TimeMode uint8
+
+const (
+	TimeModeRFC3339     TimeMode = iota // RFC3339字符串
+	TimeModeLayout                      // 自定义布局字符串
+	TimeModeEpochMillis                 // 毫秒时间戳数值
+	TimeModeEpochNanos                  // 纳秒时间戳数值
+)
+
+// 按时间输出格式格式化时间
+func formatTime(t time.Time, mode TimeMode, layout string) any {
+	switch mode {
+	case TimeModeLayout:
+		return t.Format(layout)
+	case TimeModeEpochMillis:
+		return t.UnixMilli()
+	case TimeModeEpochNanos:
+		return t.UnixNano()
+	default:
+		return t.Format(time.RFC3339)
+	}
+}
+
+// JSONFormatter json格式化器，全局字段及记录字段平铺在顶层
+type JSONFormatter struct {
+	LevelNum   bool     // 是否额外输出数字等级level_num，便于按等级范围查询
+	TimeMode   TimeMode // 时间输出格式
+	TimeLayout string   // TimeMode为TimeModeLayout时使用的布局
+}
+
+// Format 格式化
+func (self *JSONFormatter) Format(entry *Entry) (string, error) {
+	fields := make([]Field, 0, len(entry.Globals)+len(entry.Fields)+4)
+	fields = append(fields, Field{Key: "level", Value: entry.Level.String()})
+	if self.LevelNum {
+		fields = append(fields, Field{Key: "level_num", Value: uint8(entry.Level)})
+	}
+	fields = append(
+		fields,
+		Field{Key: "time", Value: formatTime(entry.Time, self.TimeMode, self.TimeLayout)},
+		Field{Key: "caller", Value: entry.Caller},
+	)
+	fields = append(fields, entry.Globals...)
+	fields = append(fields, entry.Fields...)
+
+	var buf bytes.Buffer
+	if err := writeJSONObject(&buf, fields); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
+
+// 按字段顺序写入json对象
+func writeJSONObject(buf *bytes.Buffer, fields []Field) error {
+	buf.WriteByte('{')
+	for i, field := range fields {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		key, err := json.Marshal(field.Key)
+		if err != nil {
+			return err
+		}
+		buf.Write(key)
+		buf.WriteByte(':')
+		if err = writeJSONValue(buf, field.Value); err != nil {
+			return err
+		}
+	}
+	buf.WriteByte('}')
+	return nil
+}
+
+// 写入json值，无法序列化的值退化为其字符串形式
+func writeJSONValue(buf *bytes.Buffer, v any) error {
+	// 注册的编码器优先于内置的处理方式（如异常类型）
+	if encoder, ok := lookupEncoder(v); ok {
+		// 编码结果与原值同类型时按原值处理，避免无限递归
+		if _, value := encoder(v); reflect.TypeOf(value) != reflect.TypeOf(v) {
+			return writeJSONValue(buf, value)
+		}
+	}
+
+	switch v := v.(type) {
+	case []Field:
+		return writeJSONObject(buf, v)
+	case error:
+		return writeJSONValue(buf, v.Error())
+	default:
+		if rv := reflect.ValueOf(v); rv.Kind() == reflect.Map && !rv.IsNil() {
+			return writeJSONObject(buf, mapToSortedFields(rv))
+		}
+		data, err := json.Marshal(v)
+		if err != nil {
+			data, err = json.Marshal(fmt.Sprintf("%v", v))
+			if err != nil {
+				return err
+			}
+		}
+		buf.Write(data)
+		return nil
+	}
+}
+
+// gcp日志的severity
+var gcpSeverityMap = [...]string{
+	LogLevelDebug:   "DEBUG",
+	LogLevelInfo:    "INFO",
+	LogLevelWarn:    "WARNING",
+	LogLevelError:   "ERROR",
+	LogLevelKeyword: "NOTICE",
+}
+
+// GCPFormatter Google Cloud Logging结构化日志格式化器，msg字段作为message输出
+type GCPFormatter struct{}
+
+// Format 格式化
+func (self *GCPFormatter) Format(entry *Entry) (string, error) {
+	severity := "DEFAULT"
+	if int(entry.Level) < len(gcpSeverityMap) {
+		severity = gcpSeverityMap[entry.Level]
+	}
+
+	var message any = ""
+	others := make([]Field, 0, len(entry.Globals)+len(entry.Fields))
+	others = append(others, entry.Globals...)
+	for _, field := range entry.Fields {
+		if field.Key == "msg" {
+			message = field.Value
+		} else {
+			others = append(others, field)
+		}
+	}
+
+	location := []Field{{Key: "file", Value: entry.Caller}}
+	if i := strings.LastIndexByte(entry.Caller, ':'); i >= 0 {
+		if _, err := strconv.Atoi(entry.Caller[i+1:]); err == nil {
+			location = []Field{{Key: "file", Value: entry.Caller[:i]}, {Key: "line", Value: entry.Caller[i+1:]}}
+		}
+	}
+
+	fields := make([]Field, 0, len(others)+4)
+	fields = append(
+		fields,
+		Field{Key: "severity", Value: severity},
+		Field{Key: "message", Value: message},
+		Field{Key: "timestamp", Value: entry.Time.Format(time.RFC3339Nano)},
+		Field{Key: "logging.googleapis.com/sourceLocation", Value: location},
+	)
+	fields = append(fields, others...)
+
+	var buf bytes.Buffer
+	if err := writeJSONObject(&buf, fields); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
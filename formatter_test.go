@@ -0,0 +1,155 @@
+package logs
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+)
+
+// 将单行json解析为对象
+func decodeJSON(t *testing.T, s string) map[string]any {
+	t.Helper()
+	var m map[string]any
+	if err := json.Unmarshal([]byte(s), &m); err != nil {
+		t.Fatalf("invalid json %q: %v", s, err)
+	}
+	return m
+}
+
+func TestJSONFormatterLevelNum(t *testing.T) {
+	formatter := &JSONFormatter{LevelNum: true}
+	for level := LogLevelDebug; level <= LogLevelKeyword; level++ {
+		s, err := formatter.Format(&Entry{Level: level, Time: time.Unix(0, 0)})
+		if err != nil {
+			t.Fatal(err)
+		}
+		m := decodeJSON(t, s)
+		if m["level_num"] != float64(level) || m["level"] != level.String() {
+			t.Fatalf("level %s: got level=%v level_num=%v", level, m["level"], m["level_num"])
+		}
+	}
+
+	s, _ := (&JSONFormatter{}).Format(&Entry{Level: LogLevelWarn, Time: time.Unix(0, 0)})
+	if _, ok := decodeJSON(t, s)["level_num"]; ok {
+		t.Fatalf("level_num should be off by default: %q", s)
+	}
+}
+
+func TestGCPFormatter(t *testing.T) {
+	formatter := &GCPFormatter{}
+	want := map[LogLevel]string{
+		LogLevelDebug:   "DEBUG",
+		LogLevelInfo:    "INFO",
+		LogLevelWarn:    "WARNING",
+		LogLevelError:   "ERROR",
+		LogLevelKeyword: "NOTICE",
+	}
+	for level, severity := range want {
+		s, err := formatter.Format(&Entry{
+			Level:   level,
+			Time:    time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC),
+			Caller:  "pkg/file.go:12",
+			Globals: []Field{{Key: "app", Value: "svc"}},
+			Fields:  []Field{{Key: "msg", Value: "hello"}, {Key: "a", Value: 1}},
+		})
+		if err != nil {
+			t.Fatalf("format %s: %v", level, err)
+		}
+		m := decodeJSON(t, s)
+		if m["severity"] != severity {
+			t.Fatalf("level %s: want severity %q, got %v", level, severity, m["severity"])
+		}
+		if m["message"] != "hello" {
+			t.Fatalf("want message hello, got %v", m["message"])
+		}
+		if m["timestamp"] != "2024-01-02T03:04:05Z" {
+			t.Fatalf("want RFC3339 timestamp, got %v", m["timestamp"])
+		}
+		loc, ok := m["logging.googleapis.com/sourceLocation"].(map[string]any)
+		if !ok || loc["file"] != "pkg/file.go" || loc["line"] != "12" {
+			t.Fatalf("unexpected sourceLocation: %v", m["logging.googleapis.com/sourceLocation"])
+		}
+		if m["app"] != "svc" || m["a"] != float64(1) {
+			t.Fatalf("other fields should be kept: %s", s)
+		}
+		if _, ok := m["msg"]; ok {
+			t.Fatalf("msg should be emitted as message: %s", s)
+		}
+	}
+}
+
+func TestGCPFormatterLogger(t *testing.T) {
+	logger, buf := newTestLogger(t, LogLevelDebug)
+	logger.SetFormatter(&GCPFormatter{})
+	_ = logger.Warnf(0, "hi %d", 1)
+	m := decodeJSON(t, strings.TrimSpace(buf.String()))
+	if m["severity"] != "WARNING" || m["message"] != "hi 1" {
+		t.Fatalf("unexpected record: %s", buf.String())
+	}
+	loc, _ := m["logging.googleapis.com/sourceLocation"].(map[string]any)
+	if file, _ := loc["file"].(string); !strings.HasSuffix(file, "formatter_test.go") {
+		t.Fatalf("sourceLocation should point to the caller: %s", buf.String())
+	}
+}
+
+func TestJSONFormatterTimeMode(t *testing.T) {
+	tm := time.Date(2024, 1, 2, 3, 4, 5, 6000000, time.UTC)
+	cases := []struct {
+		formatter *JSONFormatter
+		want      string
+	}{
+		{&JSONFormatter{}, `"time":"2024-01-02T03:04:05Z"`},
+		{&JSONFormatter{TimeMode: TimeModeLayout, TimeLayout: "2006/01/02"}, `"time":"2024/01/02"`},
+		{&JSONFormatter{TimeMode: TimeModeEpochMillis}, fmt.Sprintf(`"time":%d,`, tm.UnixMilli())},
+		{&JSONFormatter{TimeMode: TimeModeEpochNanos}, fmt.Sprintf(`"time":%d,`, tm.UnixNano())},
+	}
+	for _, c := range cases {
+		s, err := c.formatter.Format(&Entry{Level: LogLevelInfo, Time: tm})
+		if err != nil {
+			t.Fatalf("format: %v", err)
+		}
+		if !strings.Contains(s, c.want) {
+			t.Fatalf("time mode %d: want %s in %s", c.formatter.TimeMode, c.want, s)
+		}
+	}
+}
+
+func TestJSONFormatterEpochMillisIsNumber(t *testing.T) {
+	base := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	defer func(fn func() time.Time) { now = fn }(now)
+	now = func() time.Time { return base }
+
+	logger, buf := newTestLogger(t, LogLevelDebug)
+	logger.SetFormatter(&JSONFormatter{TimeMode: TimeModeEpochMillis})
+	_ = logger.Info(0, "a", 1)
+	m := decodeJSON(t, strings.TrimSpace(buf.String()))
+	if m["time"] != float64(base.UnixMilli()) {
+		t.Fatalf("want numeric time %d, got %#v", base.UnixMilli(), m["time"])
+	}
+}
+
+func TestSchemaVersion(t *testing.T) {
+	logger, buf := newTestLogger(t, LogLevelDebug)
+	logger.SetFormatter(&JSONFormatter{})
+	_ = logger.Info(0, "msg", "x")
+	if _, ok := decodeJSON(t, strings.TrimSpace(buf.String()))["schema_version"]; ok {
+		t.Fatalf("schema version should be omitted when unset: %s", buf.String())
+	}
+
+	buf.Reset()
+	logger.SetSchemaVersion("2")
+	_ = logger.Info(0, "msg", "x")
+	if m := decodeJSON(t, strings.TrimSpace(buf.String())); m["schema_version"] != "2" {
+		t.Fatalf("want schema_version 2, got %v", m["schema_version"])
+	}
+
+	// 默认文本格式不输出
+	text, textBuf := newTestLogger(t, LogLevelDebug)
+	text.SetSchemaVersion("2")
+	_ = text.Info(0, "msg", "x")
+	if strings.Contains(textBuf.String(), "schema_version") {
+		t.Fatalf("text output should not carry the schema version: %q", textBuf.String())
+	}
+}
@@ -0,0 +1,52 @@
+package logs
+
+import (
+	"bytes"
+	"runtime"
+	"strconv"
+	"sync"
+)
+
+// 按goroutine id绑定的日志管理器
+var (
+	goroutineLoggerLock sync.RWMutex
+	goroutineLoggers    = make(map[uint64]*Logger)
+)
+
+// 获取当前goroutine的id，解析自runtime.Stack的首行"goroutine N [...]"
+func goid() uint64 {
+	buf := make([]byte, 64)
+	buf = buf[:runtime.Stack(buf, false)]
+	buf = bytes.TrimPrefix(buf, []byte("goroutine "))
+	if i := bytes.IndexByte(buf, ' '); i >= 0 {
+		buf = buf[:i]
+	}
+	id, _ := strconv.ParseUint(string(buf), 10, 64)
+	return id
+}
+
+// SetGoroutineLogger 为当前goroutine绑定日志管理器，返回解除绑定的函数，
+// 必须在goroutine结束前调用该函数，否则绑定会一直保留造成泄漏，通常配合defer使用
+func SetGoroutineLogger(logger *Logger) (done func()) {
+	id := goid()
+	goroutineLoggerLock.Lock()
+	goroutineLoggers[id] = logger
+	goroutineLoggerLock.Unlock()
+	return func() {
+		goroutineLoggerLock.Lock()
+		delete(goroutineLoggers, id)
+		goroutineLoggerLock.Unlock()
+	}
+}
+
+// GoroutineLogger 获取当前goroutine绑定的日志管理器，未绑定时返回全局默认日志管理器
+func GoroutineLogger() *Logger {
+	id := goid()
+	goroutineLoggerLock.RLock()
+	logger, ok := goroutineLoggers[id]
+	goroutineLoggerLock.RUnlock()
+	if !ok {
+		return Default()
+	}
+	return logger
+}
@@ -0,0 +1,41 @@
+package logs
+
+import (
+	"io"
+	"sync"
+	"testing"
+)
+
+// 在嵌套调用中获取当前goroutine的日志管理器
+func nestedGoroutineLogger(depth int) *Logger {
+	if depth == 0 {
+		return GoroutineLogger()
+	}
+	return nestedGoroutineLogger(depth - 1)
+}
+
+func TestGoroutineLogger(t *testing.T) {
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			logger := NewLogger(LogLevelDebug, io.Discard)
+			done := SetGoroutineLogger(logger)
+			if got := nestedGoroutineLogger(5); got != logger {
+				t.Errorf("nested call should get the goroutine's logger, got %p want %p", got, logger)
+			}
+			done()
+			if got := nestedGoroutineLogger(1); got != Default() {
+				t.Errorf("unbound goroutine should get the default logger, got %p", got)
+			}
+		}()
+	}
+	wg.Wait()
+
+	goroutineLoggerLock.RLock()
+	defer goroutineLoggerLock.RUnlock()
+	if n := len(goroutineLoggers); n != 0 {
+		t.Fatalf("all bindings should be released, %d left", n)
+	}
+}
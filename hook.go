@@ -0,0 +1,46 @@
+package logs
+
+// Filter 过滤器，在等级检查通过后、处理器之前调用，返回false则丢弃该记录，调用结束后不能继续持有记录
+type Filter func(entry *Entry) bool
+
+// Processor 处理器，在记录渲染前调用，可修改记录，调用结束后不能继续持有记录
+type Processor func(entry *Entry)
+
+// Hook 钩子，在记录写入后调用，调用结束后不能继续持有记录
+type Hook func(entry *Entry)
+
+// AddFilter 添加过滤器
+func (self *Logger) AddFilter(filter Filter) {
+	filters := make([]Filter, len(self.filters), len(self.filters)+1)
+	copy(filters, self.filters)
+	self.filters = append(filters, filter)
+}
+
+// AddProcessor 添加处理器
+func (self *Logger) AddProcessor(processor Processor) {
+	processors := make([]Processor, len(self.processors), len(self.processors)+1)
+	copy(processors, self.processors)
+	self.processors = append(processors, processor)
+}
+
+// SetProcessors 替换全部处理器
+func (self *Logger) SetProcessors(processors ...Processor) {
+	self.processors = append([]Processor(nil), processors...)
+}
+
+// AddHook 添加钩子
+func (self *Logger) AddHook(hook Hook) {
+	hooks := make([]Hook, len(self.hooks), len(self.hooks)+1)
+	copy(hooks, self.hooks)
+	self.hooks = append(hooks, hook)
+}
+
+// Hooks 获取全部钩子
+func (self *Logger) Hooks() []Hook {
+	return append([]Hook(nil), self.hooks...)
+}
+
+// SetHooks 替换全部钩子
+func (self *Logger) SetHooks(hooks ...Hook) {
+	self.hooks = append([]Hook(nil), hooks...)
+}
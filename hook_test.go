@@ -0,0 +1,93 @@
+package logs
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestChildInheritsHooksAndProcessors(t *testing.T) {
+	logger, buf := newTestLogger(t, LogLevelInfo)
+	var parentCalls, childCalls int
+	logger.AddHook(func(entry *Entry) { parentCalls++ })
+	logger.AddProcessor(func(entry *Entry) {
+		entry.Fields = append(entry.Fields, Field{Key: "p", Value: 1})
+	})
+
+	child := logger.NewGroup()
+	_ = child.Info(0, "a", 1)
+	if parentCalls != 1 {
+		t.Fatalf("parent hook should fire for child records, got %d calls", parentCalls)
+	}
+	if !strings.Contains(buf.String(), "a=1 p=1") {
+		t.Fatalf("parent processor should apply to child records: %q", buf.String())
+	}
+
+	// 子日志管理器替换钩子及处理器不影响父日志管理器
+	buf.Reset()
+	child.SetHooks(func(entry *Entry) { childCalls++ })
+	child.SetProcessors()
+	_ = child.Info(0, "a", 1)
+	_ = logger.Info(0, "a", 1)
+	if parentCalls != 2 || childCalls != 1 {
+		t.Fatalf("want parent=2 child=1 hook calls, got parent=%d child=%d", parentCalls, childCalls)
+	}
+	if strings.Count(buf.String(), "p=1") != 1 {
+		t.Fatalf("child processors should be replaced: %q", buf.String())
+	}
+
+	// 子日志管理器追加的钩子不影响父日志管理器
+	child.AddHook(func(entry *Entry) {})
+	if len(logger.Hooks()) != 1 || len(child.Hooks()) != 2 {
+		t.Fatalf("hooks leaked between parent and child: parent=%d child=%d", len(logger.Hooks()), len(child.Hooks()))
+	}
+}
+
+func TestChildInheritsFormatterAndLevel(t *testing.T) {
+	logger, buf := newTestLogger(t, LogLevelWarn)
+	logger.SetFormatter(&JSONFormatter{})
+	child := logger.NewGroup()
+
+	_ = child.Info(0, "a", 1)
+	_ = child.Warn(0, "a", 1)
+	if out := buf.String(); strings.Count(out, "\n") != 1 || !strings.HasPrefix(out, `{"level":"WARN"`) {
+		t.Fatalf("child should inherit level and formatter: %q", out)
+	}
+
+	buf.Reset()
+	child.SetFormatter(nil)
+	_ = logger.Warn(0, "a", 1)
+	if !strings.HasPrefix(buf.String(), "{") {
+		t.Fatalf("child formatter override leaked to parent: %q", buf.String())
+	}
+}
+
+func TestAddFilter(t *testing.T) {
+	logger, buf := newTestLogger(t, LogLevelInfo)
+	var hooked []string
+	logger.AddHook(func(entry *Entry) { hooked = append(hooked, entry.Fields[0].Value.(string)) })
+	var filtered int
+	logger.AddFilter(func(entry *Entry) bool {
+		filtered++
+		for _, field := range entry.Fields {
+			if field.Key == "path" && field.Value == "/health" {
+				return false
+			}
+		}
+		return true
+	})
+
+	_ = logger.Info(0, "path", "/health")
+	_ = logger.Info(0, "path", "/api")
+	// 等级检查在过滤器之前
+	_ = logger.Debug(0, "path", "/debug")
+
+	if strings.Contains(buf.String(), "/health") || !strings.Contains(buf.String(), "path=/api") {
+		t.Fatalf("filtered record should be dropped: %q", buf.String())
+	}
+	if len(hooked) != 1 || hooked[0] != "/api" {
+		t.Fatalf("hooks should only see kept records, got %v", hooked)
+	}
+	if filtered != 2 {
+		t.Fatalf("filters should run after the level check, ran %d times", filtered)
+	}
+}
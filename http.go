@@ -0,0 +1,93 @@
+package logs
+
+import (
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/gookit/color"
+)
+
+// 需要脱敏的请求头
+var sensitiveHeaders = map[string]struct{}{
+	"Authorization":       {},
+	"Proxy-Authorization": {},
+	"Cookie":              {},
+	"Set-Cookie":          {},
+	"X-Api-Key":           {},
+}
+
+// 脱敏后的值
+const redactedValue = "***"
+
+// Header 将请求头作为结构化字段，allow为允许输出的请求头（为空则输出全部），敏感请求头的值会被脱敏
+func Header(key string, header http.Header, allow ...string) Field {
+	names := make([]string, 0, len(header))
+	if len(allow) == 0 {
+		for name := range header {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+	} else {
+		for _, name := range allow {
+			names = append(names, http.CanonicalHeaderKey(name))
+		}
+	}
+
+	var fields []Field
+	for _, name := range names {
+		values, ok := header[name]
+		if !ok {
+			continue
+		}
+		if _, ok := sensitiveHeaders[http.CanonicalHeaderKey(name)]; ok {
+			fields = append(fields, Field{Key: name, Value: redactedValue})
+		} else if len(values) == 1 {
+			fields = append(fields, Field{Key: name, Value: values[0]})
+		} else {
+			fields = append(fields, Field{Key: name, Value: values})
+		}
+	}
+	return Field{Key: key, Value: fields}
+}
+
+// http状态码字段值
+type statusValue int
+
+// Status http状态码字段，文本格式为"200 OK"并在彩色格式下按类别着色，json格式为数值
+func Status(code int) Field {
+	return Field{Key: "status", Value: statusValue(code)}
+}
+
+func (self statusValue) String() string {
+	if text := http.StatusText(int(self)); text != "" {
+		return strconv.Itoa(int(self)) + " " + text
+	}
+	return strconv.Itoa(int(self))
+}
+
+func (self statusValue) MarshalJSON() ([]byte, error) {
+	return []byte(strconv.Itoa(int(self))), nil
+}
+
+// 按类别着色：2xx绿色，3xx青色，4xx黄色，5xx红色
+func (self statusValue) colorCode() string {
+	switch int(self) / 100 {
+	case 2:
+		return color.Green.String()
+	case 3:
+		return color.Cyan.String()
+	case 4:
+		return color.Yellow.String()
+	case 5:
+		return color.Red.String()
+	default:
+		return ""
+	}
+}
+
+// Method http请求方法字段，统一为大写
+func Method(method string) Field {
+	return Field{Key: "method", Value: strings.ToUpper(strings.TrimSpace(method))}
+}
@@ -0,0 +1,84 @@
+package logs
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/gookit/color"
+)
+
+func TestHeaderAllowList(t *testing.T) {
+	logger, buf := newTestLogger(t, LogLevelDebug)
+	header := http.Header{}
+	header.Set("Accept", "application/json")
+	header.Set("Authorization", "Bearer secret")
+	header.Set("X-Other", "hidden")
+	_ = logger.Info(0, Header("headers", header, "accept", "authorization"))
+
+	out := buf.String()
+	if !strings.Contains(out, "headers={Accept=application/json Authorization=***}") {
+		t.Fatalf("allowed headers missing or not redacted: %q", out)
+	}
+	if strings.Contains(out, "secret") || strings.Contains(out, "X-Other") {
+		t.Fatalf("leaked header: %q", out)
+	}
+}
+
+func TestHeaderAll(t *testing.T) {
+	logger, buf := newTestLogger(t, LogLevelDebug)
+	header := http.Header{}
+	header.Set("Cookie", "id=1")
+	header.Add("X-Forwarded-For", "10.0.0.1")
+	header.Add("X-Forwarded-For", "10.0.0.2")
+	_ = logger.Info(0, Header("headers", header))
+
+	if want := "headers={Cookie=*** X-Forwarded-For=[10.0.0.1 10.0.0.2]}"; !strings.Contains(buf.String(), want) {
+		t.Fatalf("want %q in %q", want, buf.String())
+	}
+}
+
+func TestStatusAndMethod(t *testing.T) {
+	logger, buf := newTestLogger(t, LogLevelDebug)
+	_ = logger.Info(0, Method(" get"), Status(404), "x", 1)
+	if want := "method=GET status=404 Not Found x=1"; !strings.Contains(buf.String(), want) {
+		t.Fatalf("want %q in %q", want, buf.String())
+	}
+	if got := statusValue(799).String(); got != "799" {
+		t.Fatalf("unknown status should render the bare code, got %q", got)
+	}
+
+	buf.Reset()
+	logger.SetFormatter(&JSONFormatter{})
+	_ = logger.Info(0, Status(200))
+	if m := decodeJSON(t, strings.TrimSpace(buf.String())); m["status"] != float64(200) {
+		t.Fatalf("json status should be numeric, got %v", m["status"])
+	}
+}
+
+func TestStatusColorClass(t *testing.T) {
+	cases := map[int]string{
+		200: color.Green.String(),
+		302: color.Cyan.String(),
+		404: color.Yellow.String(),
+		503: color.Red.String(),
+		101: "",
+	}
+	for code, want := range cases {
+		if got := statusValue(code).colorCode(); got != want {
+			t.Fatalf("status %d: want color %q, got %q", code, want, got)
+		}
+	}
+
+	// 彩色格式下状态码按类别着色，其余部分不受影响
+	logger, _ := newTestLogger(t, LogLevelDebug)
+	entry := &Entry{Level: LogLevelInfo, Time: now(), Fields: []Field{Status(500), {Key: "x", Value: 1}}}
+	colored, _ := logger.render(entry, true)
+	if want := paint(color.Red.String(), "500 Internal Server Error"); !strings.Contains(colored, want) {
+		t.Fatalf("want %q in %q", want, colored)
+	}
+	plain, _ := logger.render(entry, false)
+	if color.ClearCode(colored) != plain {
+		t.Fatalf("colored %q should match plain %q without ansi codes", color.ClearCode(colored), plain)
+	}
+}
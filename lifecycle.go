@@ -0,0 +1,51 @@
+package logs
+
+// State 服务生命周期状态
+type State string
+
+const (
+	StateStarting State = "starting" // 启动中
+	StateReady    State = "ready"    // 就绪
+	StateDraining State = "draining" // 停止接收新请求，处理剩余请求中
+	StateStopped  State = "stopped"  // 已停止
+)
+
+// 各状态对应的日志等级
+var stateLevelMap = map[State]LogLevel{
+	StateStarting: LogLevelInfo,
+	StateReady:    LogLevelKeyword,
+	StateDraining: LogLevelWarn,
+	StateStopped:  LogLevelInfo,
+}
+
+// Transition 输出服务状态变化，reason为空时不输出原因字段
+func (self *Logger) Transition(skip uint, state State, reason string) error {
+	level, ok := stateLevelMap[state]
+	if !ok {
+		level = LogLevelInfo
+	}
+	if reason == "" {
+		return self.print(level, skip+1, "state", string(state))
+	}
+	return self.print(level, skip+1, "state", string(state), "reason", reason)
+}
+
+// Starting 以Info等级输出启动中状态
+func (self *Logger) Starting(skip uint, reason string) error {
+	return self.Transition(skip+1, StateStarting, reason)
+}
+
+// Ready 以Keyword等级输出就绪状态
+func (self *Logger) Ready(skip uint, reason string) error {
+	return self.Transition(skip+1, StateReady, reason)
+}
+
+// Draining 以Warn等级输出停止接收新请求状态
+func (self *Logger) Draining(skip uint, reason string) error {
+	return self.Transition(skip+1, StateDraining, reason)
+}
+
+// Stopped 以Info等级输出已停止状态
+func (self *Logger) Stopped(skip uint, reason string) error {
+	return self.Transition(skip+1, StateStopped, reason)
+}
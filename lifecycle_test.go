@@ -0,0 +1,46 @@
+package logs
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestTransitions(t *testing.T) {
+	logger, _ := newTestLogger(t, LogLevelDebug)
+	entries := logger.Capture(func() {
+		_ = logger.Starting(0, "")
+		_ = logger.Ready(0, "")
+		_ = logger.Draining(0, "sigterm")
+		_ = logger.Stopped(0, "")
+	})
+	want := []struct {
+		level LogLevel
+		state State
+	}{
+		{LogLevelInfo, StateStarting},
+		{LogLevelKeyword, StateReady},
+		{LogLevelWarn, StateDraining},
+		{LogLevelInfo, StateStopped},
+	}
+	if len(entries) != len(want) {
+		t.Fatalf("want %d records, got %d", len(want), len(entries))
+	}
+	for i, w := range want {
+		entry := entries[i]
+		if entry.Level != w.level {
+			t.Fatalf("%s: want level %s, got %s", w.state, w.level, entry.Level)
+		}
+		if entry.Fields[0] != (Field{Key: "state", Value: string(w.state)}) {
+			t.Fatalf("%s: unexpected state field %v", w.state, entry.Fields[0])
+		}
+		if !strings.Contains(entry.Caller, "lifecycle_test.go:") {
+			t.Fatalf("%s: position should be the call site, got %q", w.state, entry.Caller)
+		}
+	}
+	if len(entries[0].Fields) != 1 {
+		t.Fatalf("empty reason should be omitted: %v", entries[0].Fields)
+	}
+	if len(entries[2].Fields) != 2 || entries[2].Fields[1] != (Field{Key: "reason", Value: "sigterm"}) {
+		t.Fatalf("reason should be logged: %v", entries[2].Fields)
+	}
+}
@@ -1,19 +1,37 @@
 package logs
 
 import (
+	"encoding/binary"
 	"errors"
 	"fmt"
 	"io"
-	"log"
 	"os"
+	"reflect"
 	"runtime"
+	"strconv"
 	"strings"
+	"sync"
+	"sync/atomic"
+	"text/template"
 	"time"
+	"unicode/utf8"
 
 	"github.com/gookit/color"
 	"github.com/kkkunny/containers/linkedhashmap"
 )
 
+// ErrWriteTimeout 写入超时
+var ErrWriteTimeout = errors.New("log write timeout")
+
+// Framing 记录分帧方式
+type Framing uint8
+
+const (
+	FramingNewline      Framing = iota // 每条记录以换行结尾
+	FramingNone                        // 不添加换行
+	FramingLengthPrefix                // 每条记录前添加4字节大端序的长度，不添加换行
+)
+
 // LogLevel 日志等级
 type LogLevel uint8
 
@@ -33,6 +51,43 @@ var logLevelStringMap = [...]string{
 	LogLevelKeyword: " KEYWORD ",
 }
 
+// String 获取日志等级名
+func (self LogLevel) String() string {
+	if int(self) >= len(logLevelStringMap) {
+		return fmt.Sprintf("LogLevel(%d)", self)
+	}
+	return strings.TrimSpace(logLevelStringMap[self])
+}
+
+var (
+	levelAliasLock sync.RWMutex
+	levelAliases   = make(map[string]LogLevel)
+)
+
+// RegisterLevelAlias 注册日志等级别名，供ParseLevel解析，不区分大小写
+func RegisterLevelAlias(alias string, level LogLevel) {
+	levelAliasLock.Lock()
+	defer levelAliasLock.Unlock()
+	levelAliases[strings.ToLower(alias)] = level
+}
+
+// ParseLevel 解析日志等级名或已注册的别名，不区分大小写
+func ParseLevel(s string) (LogLevel, error) {
+	s = strings.TrimSpace(s)
+	for level := range logLevelStringMap {
+		if strings.EqualFold(s, LogLevel(level).String()) {
+			return LogLevel(level), nil
+		}
+	}
+
+	levelAliasLock.RLock()
+	defer levelAliasLock.RUnlock()
+	if level, ok := levelAliases[strings.ToLower(s)]; ok {
+		return level, nil
+	}
+	return 0, fmt.Errorf("unknown log level `%s`", s)
+}
+
 var logLevelColorMap = [...]color.Color{
 	LogLevelDebug:   color.Blue,
 	LogLevelInfo:    color.Green,
@@ -51,9 +106,96 @@ var logLevelStyleMap = [...]color.Style{
 
 // Logger 日志管理器
 type Logger struct {
+	levels *levelState // 等级设置，子日志管理器持有副本
+	values *linkedhashmap.LinkedHashMap[string, any]
+	writer *sink
+
+	filters         []Filter                                   // 过滤器
+	processors      []Processor                                // 处理器
+	hooks           []Hook                                     // 钩子
+	dynamics        []dynamicField                             // 动态字段
+	errorKey        string                                     // 异常信息的字段名
+	stackKey        string                                     // 异常栈的字段名
+	separator       string                                     // 等级标识与正文之间的分隔符
+	maxWidth        int                                        // 终端输出的最大行宽，0表示不限制
+	showErrorType   bool                                       // 输出异常时是否附带最内层异常的类型
+	maxStackFrames  int                                        // 异常栈最多输出的栈帧数，0表示不限制
+	alwaysStack     bool                                       // 非异常记录是否也附带调用栈
+	alwaysStackMin  LogLevel                                   // 非异常记录附带调用栈的最低等级
+	sourceContext   int                                        // 异常源码片段的上下文行数，0表示不输出
+	showDeadline    bool                                       // 带context的日志方法是否输出context的剩余时间
+	dropCancelled   bool                                       // 带context的日志方法在context已结束时是否跳过
+	autoSkip        bool                                       // 是否自动跳过本包及包装函数的栈帧确定调用位置
+	wrapperPrefixes []string                                   // 自动跳过的包装函数名前缀
+	foldKeys        bool                                       // 同一条记录中重复的键是否合并为数组
+	quoteValues     bool                                       // 默认文本格式中是否为含空格或特殊字符的字段值加引号
+	maxFields       int                                        // 每条记录的最大字段数，0表示不限制
+	showThread      bool                                       // 是否输出系统线程id
+	writers         []levelWriter                              // 附加写入器
+	framing         Framing                                    // 记录分帧方式
+	writeTimeout    time.Duration                              // 写入超时时间，0表示不限制
+	streamChunk     int                                        // 分块写入时每块的最大字节数，0表示整条写入
+	start           time.Time                                  // 创建时间，子日志管理器沿用
+	counts          *[len(logLevelStringMap)]uint64            // 各等级已输出的记录数，父子日志管理器共享
+	dropped         *uint64                                    // 因写入超时被丢弃的记录数，父子日志管理器共享
+	formatter       Formatter                                  // 格式化器，为空时使用默认的文本格式
+	schemaVersion   string                                     // 结构化输出的schema版本，为空时不输出
+	stderrFallback  bool                                       // 主输出写入失败时是否改写到标准错误
+	stderrColorful  bool                                       // 标准错误是否使用彩色格式，开启兜底时确定
+	templates       [len(logLevelStringMap)]*template.Template // 各等级的输出模板
+	samplers        [len(logLevelStringMap)]*sampler           // 各等级的采样器，为空时不采样
+}
+
+// 当前时间，便于测试时替换
+var now = time.Now
+
+// 兜底输出的标准错误，便于测试时替换
+var stderr io.Writer = os.Stderr
+
+// 等级标识与正文之间的默认分隔符
+const defaultSeparator = "| "
+
+// 本包的导入路径
+var pkgPath = reflect.TypeOf(Logger{}).PkgPath()
+
+// 可在运行时并发修改的等级设置，原子读写；放在指针后以免复制Logger时读取
+type levelState struct {
+	level         uint32 // LogLevel
+	enabledLevels uint32 // 启用的等级集合（按位），为0时使用level作为阈值
+}
+
+// 写入目标，父子日志管理器共享
+type sink struct {
+	writer   io.Writer
+	colorful bool          // 是否使用彩色格式，创建时确定
+	lock     sync.Mutex    // 写入锁，保证记录完整写入
+	buf      []byte        // 复用的写入缓冲区
+	inflight chan struct{} // 设置写入超时时，同一写入目标同时只允许一个进行中的写入
+	stallMu  sync.Mutex    // 保护stalled的修改
+	stalled  uint32        // 是否有超时后仍未完成的写入，原子读取
+}
+
+// 复用写入缓冲区的最大容量，超出时不保留
+const maxSinkBufSize = 64 << 10
+
+func newSink(writer io.Writer) *sink {
+	return &sink{
+		writer:   writer,
+		colorful: colorEnabled(writer),
+		inflight: make(chan struct{}, 1),
+	}
+}
+
+// 附加写入器，只写入不低于level的记录
+type levelWriter struct {
 	level  LogLevel
-	values *linkedhashmap.LinkedHashMap[string, string]
-	writer *log.Logger
+	writer *sink
+}
+
+// 动态字段，每条记录输出时重新求值
+type dynamicField struct {
+	key string
+	fn  func() any
 }
 
 // DefaultLogger 默认日志管理器
@@ -64,185 +206,940 @@ func DefaultLogger(debug bool, values ...any) *Logger {
 	return NewLogger(LogLevelInfo, os.Stdout, values...)
 }
 
-// NewLogger 新建日志管理器
+var defaultLogger atomic.Value
+
+func init() {
+	defaultLogger.Store(DefaultLogger(false))
+}
+
+// Default 获取全局默认日志管理器
+func Default() *Logger {
+	return defaultLogger.Load().(*Logger)
+}
+
+// SetDefault 设置全局默认日志管理器
+func SetDefault(logger *Logger) {
+	defaultLogger.Store(logger)
+}
+
+// NewLogger 新建日志管理器，已调用SetBuildInfo时附带version及commit全局字段
 func NewLogger(level LogLevel, writer io.Writer, values ...any) *Logger {
-	if len(values)%2 != 0 {
-		panic("The length of the values must be an even number")
-	}
-	valueMap := linkedhashmap.NewLinkedHashMap[string, string]()
-	for i, value := range values {
-		if i%2 != 0 {
-			valueMap.Set(values[i-1].(string), fmt.Sprintf("%v", value))
-		}
-	}
+	valueMap := linkedhashmap.NewLinkedHashMap[string, any]()
+	setItems(valueMap, buildInfoItems()...)
+	setItems(valueMap, values...)
 	return &Logger{
-		level:  level,
-		values: valueMap,
-		writer: log.New(writer, "", 0),
+		levels:    &levelState{level: uint32(level)},
+		values:    valueMap,
+		writer:    newSink(writer),
+		dropped:   new(uint64),
+		start:     now(),
+		separator: defaultSeparator,
+		errorKey:  "error",
+		stackKey:  "stack",
+		counts:    new([len(logLevelStringMap)]uint64),
 	}
 }
 
+// NewGroup 新建追加了全局字段的子日志管理器，子日志管理器继承等级、写入器、模板、处理器及钩子等设置，
+// 之后对子日志管理器的设置不会影响父日志管理器
 func (self *Logger) NewGroup(values ...any) *Logger {
-	if len(values)%2 != 0 {
-		panic("The length of the values must be an even number")
-	}
-	valueMap := linkedhashmap.NewLinkedHashMap[string, string]()
+	valueMap := linkedhashmap.NewLinkedHashMap[string, any]()
 	for iter := self.values.Begin(); iter != nil; iter.Next() {
 		valueMap.Set(iter.Key(), iter.Value())
 		if !iter.HasNext() {
 			break
 		}
 	}
-	for i, value := range values {
-		if i%2 != 0 {
-			valueMap.Set(values[i-1].(string), fmt.Sprintf("%v", value))
+	setItems(valueMap, values...)
+	logger := *self
+	logger.levels = &levelState{
+		level:         atomic.LoadUint32(&self.levels.level),
+		enabledLevels: atomic.LoadUint32(&self.levels.enabledLevels),
+	}
+	logger.values = valueMap
+	return &logger
+}
+
+// Merge 新建合并了other全局字段及动态字段的子日志管理器，键冲突时以other为准，其余设置继承自self
+func (self *Logger) Merge(other *Logger) *Logger {
+	logger := self.NewGroup()
+	for iter := other.values.Begin(); iter != nil; iter.Next() {
+		logger.values.Set(iter.Key(), iter.Value())
+		if !iter.HasNext() {
+			break
 		}
 	}
-	return &Logger{
-		level:  self.level,
-		values: valueMap,
-		writer: self.writer,
+	if len(other.dynamics) > 0 {
+		logger.dynamics = make([]dynamicField, 0, len(self.dynamics)+len(other.dynamics))
+		logger.dynamics = append(append(logger.dynamics, self.dynamics...), other.dynamics...)
+	}
+	return logger
+}
+
+// Level 获取日志等级
+func (self *Logger) Level() LogLevel {
+	return LogLevel(atomic.LoadUint32(&self.levels.level))
+}
+
+// SetLevel 设置日志等级，可在运行时并发调用
+func (self *Logger) SetLevel(level LogLevel) {
+	atomic.StoreUint32(&self.levels.level, uint32(level))
+}
+
+// SetWriter 替换写入器，是否使用彩色格式在此时确定
+func (self *Logger) SetWriter(writer io.Writer) {
+	self.writer = newSink(writer)
+}
+
+// AddWriter 添加附加写入器，只写入不低于level的记录
+func (self *Logger) AddWriter(level LogLevel, writer io.Writer) {
+	writers := make([]levelWriter, len(self.writers), len(self.writers)+1)
+	copy(writers, self.writers)
+	self.writers = append(writers, levelWriter{level: level, writer: newSink(writer)})
+}
+
+// SetEnabledLevels 仅启用指定的等级，设置后忽略等级阈值，不传参数则恢复使用阈值
+func (self *Logger) SetEnabledLevels(levels ...LogLevel) {
+	var mask uint32
+	for _, level := range levels {
+		mask |= 1 << level
+	}
+	atomic.StoreUint32(&self.levels.enabledLevels, mask)
+}
+
+// 指定等级是否启用
+func (self *Logger) enabled(level LogLevel) bool {
+	if mask := atomic.LoadUint32(&self.levels.enabledLevels); mask != 0 {
+		return mask&(1<<level) != 0
+	}
+	return self.Level() <= level
+}
+
+// WithDynamicField 新建带动态字段的子日志管理器，fn只在记录通过等级过滤后于输出时求值
+func (self *Logger) WithDynamicField(key string, fn func() any) *Logger {
+	logger := self.NewGroup()
+	logger.dynamics = make([]dynamicField, len(self.dynamics), len(self.dynamics)+1)
+	copy(logger.dynamics, self.dynamics)
+	logger.dynamics = append(logger.dynamics, dynamicField{key: key, fn: fn})
+	return logger
+}
+
+// SetSeparator 设置默认文本格式中等级标识与正文之间的分隔符，默认为"| "
+func (self *Logger) SetSeparator(sep string) {
+	self.separator = sep
+}
+
+// SetQuoteValues 设置默认文本格式中是否为空值及含空格、引号、等号或不可打印字符的字段值加引号，便于解析
+func (self *Logger) SetQuoteValues(quote bool) {
+	self.quoteValues = quote
+}
+
+// SetMaxFields 设置每条记录（含全局字段）的最大字段数，超出的字段被丢弃并以fields_truncated字段记录丢弃数量，0表示不限制
+func (self *Logger) SetMaxFields(n int) {
+	self.maxFields = n
+}
+
+// SetMaxWidth 设置终端输出的最大行宽，超出时字段部分折行显示，0表示不限制
+func (self *Logger) SetMaxWidth(width int) {
+	self.maxWidth = width
+}
+
+// SetErrorKeys 设置输出异常时异常信息及异常栈的字段名，默认为"error"和"stack"
+func (self *Logger) SetErrorKeys(errorKey, stackKey string) {
+	self.errorKey, self.stackKey = errorKey, stackKey
+}
+
+// SetShowErrorType 设置输出异常时是否附带异常链的类型字段（字段名为异常字段名加".type"）
+func (self *Logger) SetShowErrorType(show bool) {
+	self.showErrorType = show
+}
+
+// SetMaxStackFrames 设置输出带栈异常时最多输出的栈帧数（保留最靠近出错位置的栈帧），0表示不限制
+func (self *Logger) SetMaxStackFrames(n int) {
+	self.maxStackFrames = n
+}
+
+// AlwaysCaptureStack 设置等级不低于minLevel的非异常记录也附带调用栈字段，栈帧数同样受SetMaxStackFrames限制
+func (self *Logger) AlwaysCaptureStack(minLevel LogLevel) {
+	self.alwaysStack = true
+	self.alwaysStackMin = minLevel
+}
+
+// SetSourceContext 设置输出带栈异常时附带出错位置前后n行的源码片段，需要读取源文件，0表示不输出
+func (self *Logger) SetSourceContext(n int) {
+	self.sourceContext = n
+}
+
+// SetFraming 设置记录分帧方式，默认每条记录以换行结尾
+func (self *Logger) SetFraming(framing Framing) {
+	self.framing = framing
+}
+
+// SetWriteTimeout 设置写入超时时间，写入器阻塞超时的记录会被丢弃并计数，0表示不限制；
+// 写入器阻塞期间同一写入器的后续记录直接丢弃，阻塞的写入完成前不会再发起新的写入
+func (self *Logger) SetWriteTimeout(timeout time.Duration) {
+	self.writeTimeout = timeout
+}
+
+// SetStreamChunkSize 设置将记录按不超过size字节分块写入写入器（持有写入锁），适用于含超大字段的记录，0表示关闭；
+// 非彩色的默认文本格式及模板在未设置写入超时且不使用长度前缀分帧时直接渲染到写入器，不生成整条记录的字符串，
+// 其余情况（格式化器、彩色格式等）仍先完整渲染再分块写入
+func (self *Logger) SetStreamChunkSize(size int) {
+	self.streamChunk = size
+}
+
+// Dropped 获取因写入超时被丢弃的记录数
+func (self *Logger) Dropped() uint64 {
+	return atomic.LoadUint64(self.dropped)
+}
+
+// SetAutoSkip 设置是否自动确定调用位置，开启后忽略skip参数，跳过本包及AddWrapperPrefix注册的函数的栈帧
+func (self *Logger) SetAutoSkip(auto bool) {
+	self.autoSkip = auto
+}
+
+// AddWrapperPrefix 注册自动跳过的包装函数名前缀，如"github.com/xxx/app/log."
+func (self *Logger) AddWrapperPrefix(prefix string) {
+	prefixes := make([]string, len(self.wrapperPrefixes), len(self.wrapperPrefixes)+1)
+	copy(prefixes, self.wrapperPrefixes)
+	self.wrapperPrefixes = append(prefixes, prefix)
+}
+
+// SetFoldRepeatedKeys 设置同一条记录中重复的键是否合并为数组，关闭时后出现的值覆盖先出现的值
+func (self *Logger) SetFoldRepeatedKeys(fold bool) {
+	self.foldKeys = fold
+}
+
+// Stats 日志统计
+type Stats struct {
+	Start   time.Time                      // 日志管理器的创建时间
+	Counts  [len(logLevelStringMap)]uint64 // 各等级已输出的记录数
+	Dropped uint64                         // 因写入超时被丢弃的记录数
+}
+
+// Stats 获取统计信息，父子日志管理器共享计数
+func (self *Logger) Stats() Stats {
+	stats := Stats{
+		Start:   self.start,
+		Dropped: self.Dropped(),
+	}
+	for i := range stats.Counts {
+		stats.Counts[i] = atomic.LoadUint64(&self.counts[i])
+	}
+	return stats
+}
+
+// SetShowThread 设置是否输出当前goroutine所在的系统线程id，用于排查cgo及LockOSThread相关问题
+func (self *Logger) SetShowThread(show bool) {
+	self.showThread = show
+}
+
+// Flush 将写入器（包括附加写入器）中缓冲的日志落盘
+func (self *Logger) Flush() error {
+	err := flushWriter(self.writer.writer)
+	for _, w := range self.writers {
+		if e := flushWriter(w.writer.writer); err == nil {
+			err = e
+		}
+	}
+	return err
+}
+
+// 将写入器中缓冲的数据落盘
+func flushWriter(writer io.Writer) error {
+	switch writer := writer.(type) {
+	case interface{ Sync() error }:
+		return writer.Sync()
+	case interface{ Flush() error }:
+		return writer.Flush()
+	default:
+		return nil
+	}
+}
+
+// SetFormatter 设置格式化器，传入nil则恢复默认的文本格式
+func (self *Logger) SetFormatter(formatter Formatter) {
+	self.formatter = formatter
+}
+
+// SetSchemaVersion 设置结构化输出（设置了格式化器时）每条记录附带的schema_version字段，传入空字符串则不输出
+func (self *Logger) SetSchemaVersion(version string) {
+	self.schemaVersion = version
+}
+
+// SetStderrFallback 设置主输出写入失败时是否将记录重新渲染后写入标准错误，避免记录丢失，写入方法仍返回原异常
+func (self *Logger) SetStderrFallback(fallback bool) {
+	self.stderrFallback = fallback
+	if fallback {
+		self.stderrColorful = colorEnabled(stderr)
+	}
+}
+
+// SetTemplate 设置指定等级的输出模板，模板数据为*Entry，传入空字符串则恢复默认格式
+func (self *Logger) SetTemplate(level LogLevel, tmpl string) error {
+	if int(level) >= len(self.templates) {
+		return fmt.Errorf("unknown log level `%d`", level)
+	}
+	if tmpl == "" {
+		self.templates[level] = nil
+		return nil
+	}
+	t, err := template.New(level.String()).Parse(tmpl)
+	if err != nil {
+		return err
+	}
+	self.templates[level] = t
+	return nil
+}
+
+// 输出，values可为nil，fields追加在values之后
+func (self *Logger) output(level LogLevel, pos string, values *linkedhashmap.LinkedHashMap[string, any], fields ...Field) error {
+	if !self.sampled(level) {
+		return nil
+	}
+	if self.showThread {
+		if tid, ok := osThreadID(); ok && values != nil {
+			values.Set("thread", tid)
+		} else if ok {
+			fields = append(fields, Field{Key: "thread", Value: tid})
+		}
+	}
+
+	entry := getEntry()
+	defer putEntry(entry)
+	entry.Level = level
+	entry.Time = now()
+	entry.Caller = pos
+	if self.schemaVersion != "" && self.formatter != nil {
+		entry.Globals = append(entry.Globals, Field{Key: "schema_version", Value: self.schemaVersion})
+	}
+	entry.Globals = appendFields(entry.Globals, self.values)
+	for _, dynamic := range self.dynamics {
+		entry.Globals = append(entry.Globals, Field{Key: dynamic.key, Value: dynamic.fn()})
+	}
+	if values != nil {
+		entry.Fields = appendFields(entry.Fields, values)
+	}
+	entry.Fields = append(entry.Fields, fields...)
+	if total := len(entry.Globals) + len(entry.Fields); self.maxFields > 0 && total > self.maxFields {
+		if len(entry.Globals) > self.maxFields {
+			entry.Globals = entry.Globals[:self.maxFields]
+			entry.Fields = entry.Fields[:0]
+		} else {
+			entry.Fields = entry.Fields[:self.maxFields-len(entry.Globals)]
+		}
+		entry.Fields = append(entry.Fields, Field{Key: "fields_truncated", Value: total - self.maxFields})
+	}
+	for _, filter := range self.filters {
+		if !filter(entry) {
+			return nil
+		}
+	}
+	for _, processor := range self.processors {
+		processor(entry)
+	}
+	colorful := self.colorful()
+	var s string
+	var err error
+	if !self.streamable(entry, colorful) {
+		if s, err = self.render(entry, colorful); err != nil {
+			return err
+		}
+	}
+	if err = self.writeRecord(self.writer, entry, colorful, s); err != nil {
+		if self.stderrFallback {
+			self.writeStderr(entry, colorful, s)
+		}
+		return err
+	}
+	atomic.AddUint64(&self.counts[entry.Level], 1)
+	for _, w := range self.writers {
+		if entry.Level < w.level {
+			continue
+		}
+		ws := s
+		wcolorful := w.writer.colorful
+		if wcolorful != colorful && !self.streamable(entry, wcolorful) {
+			if ws, err = self.render(entry, wcolorful); err != nil {
+				return err
+			}
+		}
+		if err = self.writeRecord(w.writer, entry, wcolorful, ws); err != nil {
+			return err
+		}
+	}
+	for _, hook := range self.hooks {
+		hook(entry)
+	}
+	return nil
+}
+
+// 是否将记录直接渲染到写入器，见SetStreamChunkSize；写入超时时写入在其他协程中进行，可能晚于记录回收，不能直接渲染
+func (self *Logger) streamable(entry *Entry, colorful bool) bool {
+	if self.streamChunk <= 0 || colorful || self.framing == FramingLengthPrefix || self.writeTimeout > 0 {
+		return false
+	}
+	return self.templates[entry.Level] != nil || self.formatter == nil
+}
+
+// 向写入器写入一条记录，可直接渲染时渲染到写入器，否则写入已渲染的s
+func (self *Logger) writeRecord(writer *sink, entry *Entry, colorful bool, s string) error {
+	if !self.streamable(entry, colorful) {
+		return self.write(writer, s)
+	}
+	writer.lock.Lock()
+	defer writer.lock.Unlock()
+	w := &chunkWriter{writer: writer.writer, size: self.streamChunk, buf: writer.buf[:0]}
+	if cap(w.buf) < w.size {
+		w.buf = make([]byte, 0, w.size)
 	}
+	err := self.renderStream(w, entry)
+	if cap(w.buf) <= maxSinkBufSize {
+		writer.buf = w.buf
+	}
+	return err
+}
+
+// 将记录渲染到分块写入器，按分帧方式补充换行
+func (self *Logger) renderStream(w *chunkWriter, entry *Entry) error {
+	if tmpl := self.templates[entry.Level]; tmpl != nil {
+		if err := tmpl.Execute(w, entry); err != nil {
+			return err
+		}
+	} else {
+		self.writePlain(w, entry)
+	}
+	if self.framing == FramingNewline && w.last != '\n' {
+		_, _ = w.WriteString("\n")
+	}
+	return w.Flush()
+}
+
+// 分块写入器，缓冲至size字节后写入下层写入器，每次写入不超过size字节，出错后不再写入
+type chunkWriter struct {
+	writer io.Writer
+	size   int
+	buf    []byte
+	last   byte  // 最后写入的字节，换行分帧时据此判断是否需要补充换行
+	err    error // 首次写入失败的异常
+}
+
+func (self *chunkWriter) Write(p []byte) (int, error) {
+	for rest := p; len(rest) > 0 && self.err == nil; {
+		n := self.size - len(self.buf)
+		if n > len(rest) {
+			n = len(rest)
+		}
+		self.buf = append(self.buf, rest[:n]...)
+		rest = rest[n:]
+		if len(self.buf) == self.size {
+			self.flush()
+		}
+	}
+	if len(p) > 0 {
+		self.last = p[len(p)-1]
+	}
+	return len(p), self.err
+}
+
+// WriteString 写入字符串，避免转换为[]byte时复制
+func (self *chunkWriter) WriteString(s string) (int, error) {
+	for rest := s; len(rest) > 0 && self.err == nil; {
+		n := self.size - len(self.buf)
+		if n > len(rest) {
+			n = len(rest)
+		}
+		self.buf = append(self.buf, rest[:n]...)
+		rest = rest[n:]
+		if len(self.buf) == self.size {
+			self.flush()
+		}
+	}
+	if len(s) > 0 {
+		self.last = s[len(s)-1]
+	}
+	return len(s), self.err
 }
 
-// 输出
-func (self *Logger) output(level LogLevel, pos string, values *linkedhashmap.LinkedHashMap[string, string]) error {
+// Flush 写入缓冲区中剩余的内容
+func (self *chunkWriter) Flush() error {
+	if len(self.buf) > 0 {
+		self.flush()
+	}
+	return self.err
+}
+
+func (self *chunkWriter) flush() {
+	if self.err == nil {
+		_, self.err = self.writer.Write(self.buf)
+	}
+	self.buf = self.buf[:0]
+}
+
+// 向写入器写入一条记录，设置了写入超时时超时的记录会被丢弃
+func (self *Logger) write(writer *sink, s string) error {
+	if self.writeTimeout <= 0 {
+		return self.writeFrame(writer, s)
+	}
+	if atomic.LoadUint32(&writer.stalled) != 0 {
+		atomic.AddUint64(self.dropped, 1)
+		return ErrWriteTimeout
+	}
+
+	timer := time.NewTimer(self.writeTimeout)
+	defer timer.Stop()
+	select {
+	case writer.inflight <- struct{}{}:
+	case <-timer.C:
+		atomic.AddUint64(self.dropped, 1)
+		return ErrWriteTimeout
+	}
+
+	var finished bool
+	done := make(chan error, 1)
+	go func() {
+		err := self.writeFrame(writer, s)
+		writer.stallMu.Lock()
+		finished = true
+		atomic.StoreUint32(&writer.stalled, 0)
+		writer.stallMu.Unlock()
+		<-writer.inflight
+		done <- err
+	}()
+	select {
+	case err := <-done:
+		return err
+	case <-timer.C:
+	}
+
+	// 写入完成前后续记录直接丢弃
+	writer.stallMu.Lock()
+	stalled := !finished
+	if stalled {
+		atomic.StoreUint32(&writer.stalled, 1)
+	}
+	writer.stallMu.Unlock()
+	if !stalled {
+		return <-done
+	}
+	atomic.AddUint64(self.dropped, 1)
+	return ErrWriteTimeout
+}
+
+// 写入标准错误，作为主输出失败时的兜底，s为空（记录已直接渲染到写入器）时重新渲染
+func (self *Logger) writeStderr(entry *Entry, colorful bool, s string) {
+	if s == "" || self.stderrColorful != colorful {
+		var err error
+		if s, err = self.render(entry, self.stderrColorful); err != nil {
+			return
+		}
+	}
+	_, _ = io.WriteString(stderr, s+"\n")
+}
+
+// Render 将日志记录渲染为一行文本（不含换行符），优先使用等级模板，其次为格式化器，最后为默认文本格式
+func (self *Logger) Render(entry *Entry) (string, error) {
+	return self.render(entry, self.colorful())
+}
+
+// 按分帧方式写入一条记录
+func (self *Logger) writeFrame(writer *sink, s string) error {
+	writer.lock.Lock()
+	defer writer.lock.Unlock()
+	if self.streamChunk > 0 {
+		return self.writeStream(writer.writer, s)
+	}
+
+	buf := writer.buf[:0]
+	switch self.framing {
+	case FramingNone:
+		buf = append(buf, s...)
+	case FramingLengthPrefix:
+		buf = append(buf, 0, 0, 0, 0)
+		binary.BigEndian.PutUint32(buf, uint32(len(s)))
+		buf = append(buf, s...)
+	default:
+		buf = append(buf, s...)
+		if len(s) == 0 || s[len(s)-1] != '\n' {
+			buf = append(buf, '\n')
+		}
+	}
+	if cap(buf) <= maxSinkBufSize {
+		writer.buf = buf
+	}
+	_, err := writer.writer.Write(buf)
+	return err
+}
+
+// 按分帧方式将一条记录分块写入，调用方持有写入锁
+func (self *Logger) writeStream(writer io.Writer, s string) error {
+	switch self.framing {
+	case FramingNone:
+		return writeChunks(writer, s, self.streamChunk)
+	case FramingLengthPrefix:
+		var header [4]byte
+		binary.BigEndian.PutUint32(header[:], uint32(len(s)))
+		if _, err := writer.Write(header[:]); err != nil {
+			return err
+		}
+		return writeChunks(writer, s, self.streamChunk)
+	default:
+		if err := writeChunks(writer, s, self.streamChunk); err != nil {
+			return err
+		}
+		if len(s) == 0 || s[len(s)-1] != '\n' {
+			_, err := io.WriteString(writer, "\n")
+			return err
+		}
+		return nil
+	}
+}
+
+// 按size字节分块写入
+func writeChunks(writer io.Writer, s string, size int) error {
+	for len(s) > 0 {
+		n := size
+		if n > len(s) {
+			n = len(s)
+		}
+		if _, err := io.WriteString(writer, s[:n]); err != nil {
+			return err
+		}
+		s = s[n:]
+	}
+	return nil
+}
+
+// 渲染，colorful表示是否使用终端的彩色格式
+func (self *Logger) render(entry *Entry, colorful bool) (string, error) {
+	if tmpl := self.templates[entry.Level]; tmpl != nil {
+		var buf strings.Builder
+		if err := tmpl.Execute(&buf, entry); err != nil {
+			return "", err
+		}
+		return buf.String(), nil
+	}
+	if self.formatter != nil {
+		return self.formatter.Format(entry)
+	}
+
+	// 普通格式与直接渲染到写入器共用同一写入过程
+	if !colorful {
+		var buf strings.Builder
+		self.writePlain(&buf, entry)
+		return buf.String(), nil
+	}
+
 	var globalValueBuf strings.Builder
-	var i int
-	for iter := self.values.Begin(); iter != nil; iter.Next() {
+	for i, field := range entry.Globals {
+		if i > 0 {
+			globalValueBuf.WriteString(" | ")
+		}
 		globalValueBuf.WriteByte('[')
-		globalValueBuf.WriteString(iter.Key())
+		globalValueBuf.WriteString(field.Key)
 		globalValueBuf.WriteByte(']')
-		globalValueBuf.WriteString(iter.Value())
-		if !iter.HasNext() {
-			break
+		globalValueBuf.WriteString(formatValue(field.Value))
+	}
+
+	levelColor := logLevelColorMap[entry.Level].String()
+	items := make([]string, len(entry.Fields))
+	for i, field := range entry.Fields {
+		value := formatValue(field.Value)
+		if self.quoteValues && needQuote(value) {
+			value = strconv.Quote(value)
 		}
-		globalValueBuf.WriteString(" | ")
-		i++
+		if cv, ok := field.Value.(colorValue); ok {
+			// 带颜色的值渲染后恢复等级颜色
+			value = paint(cv.colorCode(), value) + fmt.Sprintf("\x1b[%sm", levelColor)
+		}
+		items[i] = field.Key + "=" + value
 	}
 
-	var valueBuf strings.Builder
-	i = 0
-	for iter := values.Begin(); iter != nil; iter.Next() {
-		valueBuf.WriteString(iter.Key())
-		valueBuf.WriteByte('=')
-		valueBuf.WriteString(iter.Value())
-		if !iter.HasNext() {
-			break
+	// 与普通格式结构一致，仅额外带有颜色代码及折行
+	badge := logLevelStringMap[entry.Level]
+	body := fmt.Sprintf(
+		"%s%s | %s | %s | ",
+		self.separator,
+		entry.Time.Format("2006-01-02 15:04:05"),
+		entry.Caller,
+		globalValueBuf.String(),
+	)
+	indent := strings.Repeat(" ", utf8.RuneCountInString(badge)) + self.separator
+	lines := []string{strings.Join(items, " ")}
+	if self.maxWidth > 0 {
+		lines = self.wrapFields(utf8.RuneCountInString(badge+body), utf8.RuneCountInString(indent), items)
+	}
+	var buf strings.Builder
+	buf.WriteString(paint(logLevelStyleMap[entry.Level].String(), badge))
+	buf.WriteString(paint(levelColor, body+lines[0]))
+	for _, line := range lines[1:] {
+		buf.WriteByte('\n')
+		buf.WriteString(paint(levelColor, indent+line))
+	}
+	return buf.String(), nil
+}
+
+// 按默认文本格式（非彩色）写入记录，字段值逐个写入w而不拼接整条记录，写入异常由w自行记录
+func (self *Logger) writePlain(w io.StringWriter, entry *Entry) {
+	_, _ = w.WriteString(logLevelStringMap[entry.Level])
+	_, _ = w.WriteString(self.separator)
+	_, _ = w.WriteString(entry.Time.Format("2006-01-02 15:04:05"))
+	_, _ = w.WriteString(" | ")
+	_, _ = w.WriteString(entry.Caller)
+	_, _ = w.WriteString(" | ")
+	for i, field := range entry.Globals {
+		if i > 0 {
+			_, _ = w.WriteString(" | ")
 		}
-		valueBuf.WriteByte(' ')
-		i++
+		_, _ = w.WriteString("[")
+		_, _ = w.WriteString(field.Key)
+		_, _ = w.WriteString("]")
+		_, _ = w.WriteString(formatValue(field.Value))
+	}
+	_, _ = w.WriteString(" | ")
+	for i, field := range entry.Fields {
+		if i > 0 {
+			_, _ = w.WriteString(" ")
+		}
+		value := formatValue(field.Value)
+		if self.quoteValues && needQuote(value) {
+			value = strconv.Quote(value)
+		}
+		_, _ = w.WriteString(field.Key)
+		_, _ = w.WriteString("=")
+		_, _ = w.WriteString(value)
 	}
+}
 
-	timeStr := time.Now().Format("2006-01-02 15:04:05")
-	var s string
-	writer := self.writer.Writer()
-	if writer == os.Stdout || writer == os.Stderr {
-		suffix := fmt.Sprintf(
-			"| %s | %s | %s | %s",
-			timeStr,
-			pos,
-			globalValueBuf.String(),
-			valueBuf.String(),
-		)
-		suffix = logLevelColorMap[level].Text(suffix)
-		s = logLevelStyleMap[level].Sprintf(logLevelStringMap[level]) + suffix
-	} else {
-		s = fmt.Sprintf(
-			"%s| %s | %s | %s | %s",
-			logLevelStringMap[level],
-			timeStr,
-			pos,
-			globalValueBuf.String(),
-			valueBuf.String(),
-		)
+// 是否使用彩色格式
+func (self *Logger) colorful() bool {
+	return self.writer.colorful
+}
+
+// 分隔线的默认宽度
+const defaultSeparatorWidth = 80
+
+// Separator 向彩色终端写入一行分隔线，用于标记新操作的开始，宽度同SetMaxWidth（未设置时为80），
+// 非终端写入器或设置了格式化器时不输出，附加写入器不输出
+func (self *Logger) Separator() error {
+	if self.formatter != nil || !self.colorful() {
+		return nil
+	}
+	width := self.maxWidth
+	if width <= 0 {
+		width = defaultSeparatorWidth
+	}
+	return self.write(self.writer, paint(color.Gray.String(), strings.Repeat("─", width)))
+}
+
+// 按最大宽度将字段折行，used为首行已占用的宽度，indent为续行悬挂缩进的宽度
+func (self *Logger) wrapFields(used, indent int, items []string) []string {
+	var lines []string
+	var line strings.Builder
+	width := used
+	for _, item := range items {
+		itemWidth := utf8.RuneCountInString(color.ClearCode(item))
+		if line.Len() > 0 {
+			if width+1+itemWidth > self.maxWidth {
+				lines = append(lines, line.String())
+				line.Reset()
+				width = indent
+			} else {
+				line.WriteByte(' ')
+				width++
+			}
+		}
+		line.WriteString(item)
+		width += itemWidth
 	}
-	return self.writer.Output(0, s)
+	return append(lines, line.String())
 }
 
 func (self *Logger) outputByStack(
-	level LogLevel, skip uint, values *linkedhashmap.LinkedHashMap[string, string],
+	level LogLevel, skip uint, values *linkedhashmap.LinkedHashMap[string, any],
 ) error {
-	_, file, line, _ := runtime.Caller(int(skip + 1))
-	return self.output(level, fmt.Sprintf("%s:%d", file, line), values)
+	if field, ok := self.stackField(level, skip+1); ok {
+		values.Set(field.Key, field.Value)
+	}
+	return self.output(level, self.caller(skip+1), values)
+}
+
+// 按AlwaysCaptureStack的设置获取调用栈字段
+func (self *Logger) stackField(level LogLevel, skip uint) (Field, bool) {
+	if !self.alwaysStack || level < self.alwaysStackMin {
+		return Field{}, false
+	}
+	return Field{Key: self.stackKey, Value: self.formatStack(StackCapturer(skip + 1))}, true
+}
+
+// 按栈帧数限制格式化栈帧
+func (self *Logger) formatStack(stacks []runtime.Frame) string {
+	return formatStack(stacks, self.maxStackFrames)
+}
+
+// 格式化栈帧，每帧一行且以换行开头，栈帧数超出limit时只保留最靠近出错位置的栈帧，limit为0表示不限制
+func formatStack(stacks []runtime.Frame, limit int) string {
+	if limit > 0 && len(stacks) > limit {
+		stacks = stacks[len(stacks)-limit:]
+	}
+
+	var stackBuffer strings.Builder
+	stackBuffer.WriteByte('\n')
+	for i, s := range stacks {
+		stackBuffer.WriteString(fmt.Sprintf("\t%s:%d", s.File, s.Line))
+		if i < len(stacks)-1 {
+			stackBuffer.WriteByte('\n')
+		}
+	}
+	return stackBuffer.String()
+}
+
+// 获取调用位置，自动跳过模式下忽略skip，取第一个不属于本包及包装函数的栈帧
+func (self *Logger) caller(skip uint) string {
+	if !self.autoSkip {
+		_, file, line, _ := runtime.Caller(int(skip + 1))
+		return fmt.Sprintf("%s:%d", file, line)
+	}
+
+	pcs := make([]uintptr, 32)
+	n := runtime.Callers(2, pcs)
+	frames := runtime.CallersFrames(pcs[:n])
+	for {
+		frame, more := frames.Next()
+		if !more || !self.isWrapperFrame(frame) {
+			return fmt.Sprintf("%s:%d", frame.File, frame.Line)
+		}
+	}
+}
+
+// 栈帧是否属于本包或注册的包装函数
+func (self *Logger) isWrapperFrame(frame runtime.Frame) bool {
+	if strings.HasPrefix(frame.Function, pkgPath+".") {
+		return true
+	}
+	for _, prefix := range self.wrapperPrefixes {
+		if strings.HasPrefix(frame.Function, prefix) {
+			return true
+		}
+	}
+	return false
 }
 
 // 检查item
-func (self *Logger) checkItems(a ...any) *linkedhashmap.LinkedHashMap[string, string] {
-	if len(a)%2 != 0 {
-		panic("The number of items needs to be an even number")
+func (self *Logger) checkItems(a ...any) *linkedhashmap.LinkedHashMap[string, any] {
+	items := linkedhashmap.NewLinkedHashMap[string, any]()
+	if !self.foldKeys {
+		setItems(items, a...)
+		return items
 	}
 
-	items := linkedhashmap.NewLinkedHashMap[string, string]()
-	for i, aa := range a {
-		if i%2 != 0 {
-			items.Set(fmt.Sprintf("%v", a[i-1]), fmt.Sprintf("%v", aa))
+	var keys []string
+	values := make(map[string][]any)
+	rangeItems(a, func(key string, value any) {
+		if _, ok := values[key]; !ok {
+			keys = append(keys, key)
+		}
+		values[key] = append(values[key], value)
+	})
+	for _, key := range keys {
+		if vs := values[key]; len(vs) == 1 {
+			items.Set(key, vs[0])
+		} else {
+			items.Set(key, vs)
 		}
 	}
 	return items
 }
 
+// 将item写入字典，item为Field或键值对
+func setItems(values *linkedhashmap.LinkedHashMap[string, any], a ...any) {
+	rangeItems(a, func(key string, value any) {
+		values.Set(key, value)
+	})
+}
+
+// 遍历item，item为Field或键值对
+func rangeItems(a []any, fn func(key string, value any)) {
+	for i := 0; i < len(a); i++ {
+		if field, ok := a[i].(Field); ok {
+			fn(field.Key, field.Value)
+			continue
+		}
+		if i+1 >= len(a) {
+			panic("The number of items needs to be an even number")
+		}
+		fn(fmt.Sprintf("%v", a[i]), a[i+1])
+		i++
+	}
+}
+
 // 打印
 func (self *Logger) print(level LogLevel, skip uint, a ...any) error {
 	items := self.checkItems(a...)
-	if self.level > level {
+	if !self.enabled(level) {
 		return nil
 	}
 	return self.outputByStack(level, skip+1, items)
 }
 
-// 格式化打印
+// 格式化打印，不经过checkItems直接输出单个msg字段
 func (self *Logger) printf(level LogLevel, skip uint, f string, a ...any) error {
-	return self.print(level, skip+1, "msg", fmt.Sprintf(f, a...))
+	if !self.enabled(level) {
+		return nil
+	}
+	msg := Field{Key: "msg", Value: fmt.Sprintf(f, a...)}
+	if field, ok := self.stackField(level, skip+1); ok {
+		return self.output(level, self.caller(skip+1), nil, msg, field)
+	}
+	return self.output(level, self.caller(skip+1), nil, msg)
 }
 
 // 打印异常
 func (self *Logger) printError(level LogLevel, skip uint, err error) error {
 	var logerr Error
 	if errors.As(err, &logerr) {
-		return self.printLogError(level, logerr)
+		return self.printLogError(level, skip+1, err, logerr)
 	} else {
-		return self.print(level, skip+1, "error", err.Error())
+		return self.print(level, skip+1, self.errorItems(err)...)
 	}
 }
 
-// 打印带栈异常
-func (self *Logger) printLogError(level LogLevel, err Error) error {
-	if self.level > level {
-		return nil
+// 异常的信息字段，开启时附带异常链的类型（不含本包的包装类型），由外到内以" > "连接
+func (self *Logger) errorItems(err error) []any {
+	if !self.showErrorType {
+		return []any{self.errorKey, err.Error()}
 	}
-
-	stacks := err.Stacks()
-
-	var stackBuffer strings.Builder
-	stackBuffer.WriteByte('\n')
-	for i, s := range stacks {
-		stackBuffer.WriteString(fmt.Sprintf("\t%s:%d", s.File, s.Line))
-		if i < len(stacks)-1 {
-			stackBuffer.WriteByte('\n')
+	var types []string
+	for e := err; e != nil; e = errors.Unwrap(e) {
+		if _, ok := e.(*logError); !ok {
+			types = append(types, reflect.TypeOf(e).String())
 		}
 	}
-
-	values := linkedhashmap.NewLinkedHashMap[string, string]()
-	values.Set("error", err.Error())
-	values.Set("stack", stackBuffer.String())
-	stack := stacks[len(stacks)-1]
-	return self.output(level, fmt.Sprintf("%s:%d", stack.File, stack.Line), values)
+	return []any{self.errorKey, err.Error(), self.errorKey + ".type", strings.Join(types, " > ")}
 }
 
-// Debug 输出Debug信息
-func (self *Logger) Debug(skip uint, a ...any) error {
-	return self.print(LogLevelDebug, skip+1, a...)
-}
+// 打印带栈异常，信息取自最外层异常以保留完整的包装链，栈帧取自logerr
+func (self *Logger) printLogError(level LogLevel, skip uint, err error, logerr Error) error {
+	if !self.enabled(level) {
+		return nil
+	}
 
-// Debugf 输出Debugf格式化信息
-func (self *Logger) Debugf(skip uint, f string, a ...any) error {
-	return self.printf(LogLevelDebug, skip+1, f, a...)
-}
+	stacks := logerr.Stacks()
+	if len(stacks) == 0 {
+		// 没有栈帧信息时退化为普通异常
+		return self.print(level, skip+1, self.errorItems(err)...)
+	}
 
-// DebugError 输出Debug异常信息
-func (self *Logger) DebugError(skip uint, err error) error {
-	return self.printError(LogLevelDebug, skip+1, err)
+	stack := stacks[len(stacks)-1]
+	values := linkedhashmap.NewLinkedHashMap[string, any]()
+	setItems(values, self.errorItems(err)...)
+	values.Set(self.stackKey, self.formatStack(stacks))
+	if self.sourceContext > 0 {
+		if snippet, ok := sourceSnippet(stack.File, stack.Line, self.sourceContext); ok {
+			values.Set("source", snippet)
+		}
+	}
+	return self.output(level, fmt.Sprintf("%s:%d", stack.File, stack.Line), values)
 }
 
 // Info 输出Info信息
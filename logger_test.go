@@ -0,0 +1,445 @@
+package logs
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"runtime"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+	"unicode/utf8"
+
+	"github.com/gookit/color"
+)
+
+// 新建写入缓冲区的日志管理器
+func newTestLogger(t *testing.T, level LogLevel, values ...any) (*Logger, *bytes.Buffer) {
+	t.Helper()
+	var buf bytes.Buffer
+	return NewLogger(level, &buf, values...), &buf
+}
+
+func TestShowThread(t *testing.T) {
+	if _, ok := osThreadID(); !ok {
+		t.Skip("thread id is not available on this platform")
+	}
+	logger, buf := newTestLogger(t, LogLevelDebug)
+	_ = logger.Info(0, "a", 1)
+	if strings.Contains(buf.String(), "thread=") {
+		t.Fatalf("thread field should be off by default: %q", buf.String())
+	}
+
+	buf.Reset()
+	logger.SetShowThread(true)
+	_ = logger.Info(0, "a", 1)
+	_ = logger.Infof(0, "b")
+	if n := strings.Count(buf.String(), " thread="); n != 2 {
+		t.Fatalf("want thread field on both records, got %d: %q", n, buf.String())
+	}
+}
+
+func TestSetTemplate(t *testing.T) {
+	logger, buf := newTestLogger(t, LogLevelDebug, "app", "x")
+	err := logger.SetTemplate(LogLevelInfo, `{{.Level}} {{range .Globals}}{{.Key}}:{{.Value}} {{end}}{{range .Fields}}{{.Key}}={{.Value}};{{end}}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_ = logger.Info(0, "a", 1, "b", 2)
+	if want := "INFO app:x a=1;b=2;\n"; buf.String() != want {
+		t.Fatalf("want %q, got %q", want, buf.String())
+	}
+
+	// 其他等级不受影响
+	buf.Reset()
+	_ = logger.Warn(0, "a", 1)
+	if !strings.HasPrefix(buf.String(), "  WARN  | ") {
+		t.Fatalf("warn should use the default format: %q", buf.String())
+	}
+
+	// 传入空字符串恢复默认格式
+	buf.Reset()
+	if err = logger.SetTemplate(LogLevelInfo, ""); err != nil {
+		t.Fatal(err)
+	}
+	_ = logger.Info(0, "a", 1)
+	if !strings.HasPrefix(buf.String(), "  INFO  | ") {
+		t.Fatalf("template should be reset: %q", buf.String())
+	}
+}
+
+func TestSetTemplateInvalid(t *testing.T) {
+	logger, _ := newTestLogger(t, LogLevelDebug)
+	if err := logger.SetTemplate(LogLevelInfo, "{{.Level"); err == nil {
+		t.Fatal("want parse error")
+	}
+	if err := logger.SetTemplate(LogLevel(100), "{{.Level}}"); err == nil {
+		t.Fatal("want error for unknown level")
+	}
+}
+
+func TestRenderMatchesOutput(t *testing.T) {
+	logger, buf := newTestLogger(t, LogLevelDebug, "app", "x")
+	var rendered string
+	logger.AddHook(func(entry *Entry) {
+		rendered, _ = logger.Render(entry)
+	})
+	_ = logger.Info(0, "a", 1)
+	if rendered+"\n" != buf.String() {
+		t.Fatalf("render %q differs from output %q", rendered, buf.String())
+	}
+}
+
+func TestWithDynamicField(t *testing.T) {
+	logger, buf := newTestLogger(t, LogLevelInfo)
+	var calls int
+	logger = logger.WithDynamicField("n", func() any {
+		calls++
+		return calls
+	})
+	_ = logger.Debug(0, "a", 1)
+	_ = logger.Info(0, "a", 1)
+	_ = logger.Infof(0, "b")
+	if calls != 2 {
+		t.Fatalf("provider should run once per emitted record, got %d calls", calls)
+	}
+	if !strings.Contains(buf.String(), "[n]1") || !strings.Contains(buf.String(), "[n]2") {
+		t.Fatalf("missing dynamic field: %q", buf.String())
+	}
+}
+
+func TestMaxWidthWrapsTerminalOutput(t *testing.T) {
+	t.Setenv("CLICOLOR_FORCE", "1")
+	logger, buf := newTestLogger(t, LogLevelDebug)
+	logger.SetMaxWidth(60)
+	_ = logger.Info(0, "aaaaaaaaaa", "1111111111", "bbbbbbbbbb", "2222222222", "c", "3")
+
+	lines := strings.Split(strings.TrimSuffix(color.ClearCode(buf.String()), "\n"), "\n")
+	if len(lines) < 2 {
+		t.Fatalf("want wrapped lines, got %q", lines)
+	}
+	for _, line := range lines[1:] {
+		if !strings.HasPrefix(line, "        | ") {
+			t.Fatalf("continuation line should be indented under the badge: %q", line)
+		}
+		if utf8.RuneCountInString(line) > 60 {
+			t.Fatalf("line exceeds max width: %q", line)
+		}
+	}
+	if !strings.Contains(strings.Join(lines, " "), "c=3") {
+		t.Fatalf("fields lost while wrapping: %q", lines)
+	}
+}
+
+func TestMaxWidthKeepsPlainOutputOnOneLine(t *testing.T) {
+	logger, buf := newTestLogger(t, LogLevelDebug)
+	logger.SetMaxWidth(20)
+	_ = logger.Info(0, "aaaaaaaaaa", "1111111111", "bbbbbbbbbb", "2222222222")
+	if n := strings.Count(buf.String(), "\n"); n != 1 {
+		t.Fatalf("plain output should stay on one line: %q", buf.String())
+	}
+}
+
+// 阻塞直到release被关闭的写入器
+type blockingWriter struct {
+	release chan struct{}
+	writes  int32
+}
+
+func (self *blockingWriter) Write(p []byte) (int, error) {
+	atomic.AddInt32(&self.writes, 1)
+	<-self.release
+	return len(p), nil
+}
+
+func TestWriteTimeoutWithBlockingWriter(t *testing.T) {
+	w := &blockingWriter{release: make(chan struct{})}
+	logger := NewLogger(LogLevelDebug, w)
+	logger.SetWriteTimeout(10 * time.Millisecond)
+
+	start := time.Now()
+	if err := logger.Info(0, "a", 1); err != ErrWriteTimeout {
+		t.Fatalf("want ErrWriteTimeout, got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Fatalf("write should return after the timeout, took %s", elapsed)
+	}
+
+	// 写入器阻塞期间后续记录直接丢弃，不会阻塞也不会新建协程
+	goroutines := runtime.NumGoroutine()
+	start = time.Now()
+	for i := 0; i < 100; i++ {
+		if err := logger.NewGroup().Info(0, "a", i); err != ErrWriteTimeout {
+			t.Fatalf("want ErrWriteTimeout, got %v", err)
+		}
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Fatalf("records should be dropped while the writer is stuck, took %s", elapsed)
+	}
+	if n := runtime.NumGoroutine(); n > goroutines {
+		t.Fatalf("goroutines grew from %d to %d", goroutines, n)
+	}
+	if n := atomic.LoadInt32(&w.writes); n != 1 {
+		t.Fatalf("want a single in-flight write, got %d", n)
+	}
+	if dropped := logger.Dropped(); dropped != 101 {
+		t.Fatalf("want 101 dropped records, got %d", dropped)
+	}
+	if err := logger.Flush(); err != nil {
+		t.Fatal(err)
+	}
+
+	// 写入器恢复后继续写入
+	close(w.release)
+	waitFor(t, func() bool { return logger.Info(0, "a", 1) == nil })
+}
+
+func TestMerge(t *testing.T) {
+	logger, buf := newTestLogger(t, LogLevelInfo, "a", 1, "b", 1)
+	other := NewLogger(LogLevelDebug, io.Discard, "b", 2, "c", 2)
+	merged := logger.Merge(other)
+
+	_ = merged.Info(0, "x", 1)
+	if !strings.Contains(buf.String(), "| [a]1 | [b]2 | [c]2 | x=1") {
+		t.Fatalf("want combined fields with other winning: %q", buf.String())
+	}
+
+	// 合并不影响原日志管理器
+	buf.Reset()
+	_ = logger.Info(0, "x", 1)
+	if !strings.Contains(buf.String(), "| [a]1 | [b]1 | x=1") {
+		t.Fatalf("receiver fields changed: %q", buf.String())
+	}
+	if merged.Level() != LogLevelInfo {
+		t.Fatalf("merged logger should keep the receiver's level, got %s", merged.Level())
+	}
+}
+
+func TestFoldRepeatedKeys(t *testing.T) {
+	logger, buf := newTestLogger(t, LogLevelDebug)
+	_ = logger.Info(0, "tag", "a", "tag", "b")
+	if !strings.HasSuffix(buf.String(), "| tag=b\n") {
+		t.Fatalf("last value should win by default: %q", buf.String())
+	}
+
+	buf.Reset()
+	logger.SetFoldRepeatedKeys(true)
+	_ = logger.Info(0, "tag", "a", "x", 1, "tag", "b")
+	if !strings.HasSuffix(buf.String(), "| tag=[a b] x=1\n") {
+		t.Fatalf("repeated key should be folded in place: %q", buf.String())
+	}
+
+	buf.Reset()
+	logger.SetFormatter(&JSONFormatter{})
+	_ = logger.Info(0, "tag", "a", "tag", "b")
+	m := decodeJSON(t, strings.TrimSpace(buf.String()))
+	tags, ok := m["tag"].([]any)
+	if !ok || len(tags) != 2 || tags[0] != "a" || tags[1] != "b" {
+		t.Fatalf("want json array [a b], got %v", m["tag"])
+	}
+}
+
+func TestRegisterLevelAlias(t *testing.T) {
+	defer func() {
+		levelAliasLock.Lock()
+		defer levelAliasLock.Unlock()
+		delete(levelAliases, "warning")
+		delete(levelAliases, "err")
+	}()
+	RegisterLevelAlias("warning", LogLevelWarn)
+	RegisterLevelAlias("ERR", LogLevelError)
+
+	cases := map[string]LogLevel{
+		"Warning": LogLevelWarn,
+		" err ":   LogLevelError,
+		"warn":    LogLevelWarn,
+		"ERROR":   LogLevelError,
+	}
+	for s, want := range cases {
+		level, err := ParseLevel(s)
+		if err != nil {
+			t.Fatalf("parse %q: %v", s, err)
+		}
+		if level != want {
+			t.Fatalf("parse %q: want %s, got %s", s, want, level)
+		}
+	}
+	if _, err := ParseLevel("fatal"); err == nil {
+		t.Fatal("unknown level should still be an error")
+	}
+}
+
+func TestSeparatorSameLayoutInBothColorModes(t *testing.T) {
+	logger, _ := newTestLogger(t, LogLevelDebug)
+	entry := &Entry{
+		Level:   LogLevelInfo,
+		Time:    time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC),
+		Caller:  "x.go:1",
+		Globals: []Field{{Key: "g", Value: 1}},
+		Fields:  []Field{{Key: "a", Value: 1}},
+	}
+	for _, sep := range []string{defaultSeparator, " ▶ "} {
+		logger.SetSeparator(sep)
+		plain, _ := logger.render(entry, false)
+		colored, _ := logger.render(entry, true)
+		if strings.Contains(plain, "\x1b") || !strings.Contains(colored, "\x1b") {
+			t.Fatalf("only the colored branch should contain ansi codes: %q %q", plain, colored)
+		}
+		if stripped := color.ClearCode(colored); stripped != plain {
+			t.Fatalf("separator %q: colored %q differs from plain %q", sep, stripped, plain)
+		}
+		if want := "  INFO  " + sep + "2024-01-02 03:04:05 | "; !strings.HasPrefix(plain, want) {
+			t.Fatalf("want prefix %q, got %q", want, plain)
+		}
+	}
+}
+
+func TestFraming(t *testing.T) {
+	logger, buf := newTestLogger(t, LogLevelDebug)
+	logger.SetFormatter(&JSONFormatter{})
+	logger.SetFraming(FramingNone)
+	_ = logger.Info(0, "a", 1)
+	if bytes.HasSuffix(buf.Bytes(), []byte("\n")) {
+		t.Fatalf("FramingNone should not add a newline: %q", buf.String())
+	}
+	decodeJSON(t, buf.String())
+
+	buf.Reset()
+	logger.SetFraming(FramingLengthPrefix)
+	_ = logger.Info(0, "a", 1)
+	_ = logger.Info(0, "b", 2)
+	// 逐帧解析
+	data := buf.Bytes()
+	for i := 0; len(data) > 0; i++ {
+		if len(data) < 4 {
+			t.Fatalf("frame %d: truncated length prefix", i)
+		}
+		n := int(binary.BigEndian.Uint32(data))
+		if len(data) < 4+n {
+			t.Fatalf("frame %d: want %d bytes, only %d left", i, n, len(data)-4)
+		}
+		frame := string(data[4 : 4+n])
+		if strings.HasSuffix(frame, "\n") {
+			t.Fatalf("frame %d should not end with a newline: %q", i, frame)
+		}
+		decodeJSON(t, frame)
+		data = data[4+n:]
+	}
+}
+
+func TestQuoteValues(t *testing.T) {
+	logger, buf := newTestLogger(t, LogLevelDebug)
+	_ = logger.Info(0, "a", "hello world", "b", "simple")
+	if !strings.HasSuffix(buf.String(), "| a=hello world b=simple\n") {
+		t.Fatalf("values should be unquoted by default: %q", buf.String())
+	}
+
+	buf.Reset()
+	logger.SetQuoteValues(true)
+	_ = logger.Info(0, "a", "hello world", "b", "simple", "c", "", "d", "x=y", "e", "tab\there", "f", 42)
+	if want := `| a="hello world" b=simple c="" d="x=y" e="tab\there" f=42` + "\n"; !strings.HasSuffix(buf.String(), want) {
+		t.Fatalf("want suffix %q, got %q", want, buf.String())
+	}
+}
+
+func TestPrintfFastPath(t *testing.T) {
+	base := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	defer func(fn func() time.Time) { now = fn }(now)
+	now = func() time.Time { return base }
+
+	// 输出与经过字段表的通用路径逐字节一致（调用位置除外）
+	fast, fastBuf := newTestLogger(t, LogLevelDebug, "app", "x")
+	slow, slowBuf := newTestLogger(t, LogLevelDebug, "app", "x")
+	_ = fast.Infof(0, "user %s id %d", "kkkunny", 1)
+	_ = slow.Info(0, "msg", fmt.Sprintf("user %s id %d", "kkkunny", 1))
+	stripCaller := func(s string) string {
+		return regexp.MustCompile(`logger_test\.go:\d+`).ReplaceAllString(s, "")
+	}
+	if got, want := stripCaller(fastBuf.String()), stripCaller(slowBuf.String()); got != want {
+		t.Fatalf("printf output %q differs from %q", got, want)
+	}
+
+	// 跳过字段表后分配次数更少
+	fast.SetWriter(io.Discard)
+	slow.SetWriter(io.Discard)
+	fastAllocs := testing.AllocsPerRun(100, func() {
+		_ = fast.Infof(0, "user %s id %d", "kkkunny", 1)
+	})
+	slowAllocs := testing.AllocsPerRun(100, func() {
+		_ = slow.Info(0, "msg", fmt.Sprintf("user %s id %d", "kkkunny", 1))
+	})
+	if fastAllocs >= slowAllocs {
+		t.Fatalf("printf fast path should allocate less: %v allocs vs %v", fastAllocs, slowAllocs)
+	}
+}
+
+func TestMaxFields(t *testing.T) {
+	logger, buf := newTestLogger(t, LogLevelDebug, "g", 1)
+	logger.SetMaxFields(3)
+	_ = logger.Info(0, "a", 1, "b", 2)
+	if strings.Contains(buf.String(), "fields_truncated") {
+		t.Fatalf("records within the limit should be untouched: %q", buf.String())
+	}
+
+	buf.Reset()
+	_ = logger.Info(0, "a", 1, "b", 2, "c", 3, "d", 4)
+	if !strings.HasSuffix(buf.String(), "| [g]1 | a=1 b=2 fields_truncated=2\n") {
+		t.Fatalf("extra fields should be dropped with a marker: %q", buf.String())
+	}
+
+	// 子日志管理器的全局字段也计入
+	buf.Reset()
+	child := logger.NewGroup("h", 2, "i", 3, "j", 4)
+	_ = child.Info(0, "a", 1)
+	if !strings.Contains(buf.String(), "fields_truncated=2") || strings.Contains(buf.String(), "a=1") {
+		t.Fatalf("global fields should count toward the limit: %q", buf.String())
+	}
+}
+
+// 总是写入失败的写入器
+type failingWriter struct{}
+
+func (failingWriter) Write([]byte) (int, error) { return 0, errors.New("boom") }
+
+func TestStderrFallback(t *testing.T) {
+	var captured bytes.Buffer
+	defer func(w io.Writer) { stderr = w }(stderr)
+	stderr = &captured
+
+	logger := NewLogger(LogLevelDebug, failingWriter{})
+	if err := logger.Info(0, "msg", "lost"); err == nil {
+		t.Fatal("write error should be returned")
+	}
+	if captured.Len() != 0 {
+		t.Fatalf("fallback should be opt-in: %q", captured.String())
+	}
+
+	logger.SetStderrFallback(true)
+	if err := logger.Info(0, "msg", "saved"); err == nil {
+		t.Fatal("write error should still be returned with the fallback")
+	}
+	if !strings.HasSuffix(captured.String(), "| msg=saved\n") {
+		t.Fatalf("record should be written to stderr: %q", captured.String())
+	}
+}
+
+func TestStderrFallbackRerendersColor(t *testing.T) {
+	var captured bytes.Buffer
+	defer func(w io.Writer) { stderr = w }(stderr)
+	stderr = &captured
+
+	// 主输出为彩色，标准错误为普通格式时重新渲染
+	t.Setenv("CLICOLOR_FORCE", "1")
+	logger := NewLogger(LogLevelDebug, failingWriter{})
+	t.Setenv("CLICOLOR_FORCE", "")
+	t.Setenv("NO_COLOR", "1")
+	logger.SetStderrFallback(true)
+	_ = logger.Info(0, "msg", "saved")
+	if strings.Contains(captured.String(), "\x1b") || !strings.Contains(captured.String(), "msg=saved") {
+		t.Fatalf("stderr record should be rendered without color: %q", captured.String())
+	}
+}
@@ -0,0 +1,58 @@
+// Package logtest 日志相关的测试辅助
+package logtest
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/kkkunny/logs"
+)
+
+// Guard 由ExpectNoErrors返回，用于放行预期内的Warn/Error记录
+type Guard struct {
+	lock    sync.Mutex
+	allowed []func(entry *logs.Entry) bool
+}
+
+// Allow 放行满足fn的记录
+func (self *Guard) Allow(fn func(entry *logs.Entry) bool) {
+	self.lock.Lock()
+	defer self.lock.Unlock()
+	self.allowed = append(self.allowed, fn)
+}
+
+// 记录是否被放行
+func (self *Guard) isAllowed(entry *logs.Entry) bool {
+	self.lock.Lock()
+	defer self.lock.Unlock()
+	for _, fn := range self.allowed {
+		if fn(entry) {
+			return true
+		}
+	}
+	return false
+}
+
+// ExpectNoErrors 为logger安装钩子，测试期间输出未放行的Warn或Error记录时使测试失败，测试结束后恢复原有钩子
+func ExpectNoErrors(t testing.TB, logger *logs.Logger) *Guard {
+	t.Helper()
+
+	guard := new(Guard)
+	hooks := logger.Hooks()
+	logger.AddHook(func(entry *logs.Entry) {
+		if entry.Level < logs.LogLevelWarn || entry.Level > logs.LogLevelError || guard.isAllowed(entry) {
+			return
+		}
+		fields := make([]string, len(entry.Fields))
+		for i, field := range entry.Fields {
+			fields[i] = fmt.Sprintf("%s=%v", field.Key, field.Value)
+		}
+		t.Errorf("unexpected %s record at %s: %s", entry.Level, entry.Caller, strings.Join(fields, " "))
+	})
+	t.Cleanup(func() {
+		logger.SetHooks(hooks...)
+	})
+	return guard
+}
@@ -0,0 +1,87 @@
+package logtest
+
+import (
+	"fmt"
+	"io"
+	"testing"
+
+	"github.com/kkkunny/logs"
+)
+
+// 记录失败信息而不终止测试的testing.TB
+type recordingTB struct {
+	testing.TB
+	errors   []string
+	cleanups []func()
+}
+
+func (self *recordingTB) Helper() {}
+
+func (self *recordingTB) Errorf(format string, a ...any) {
+	self.errors = append(self.errors, fmt.Sprintf(format, a...))
+}
+
+func (self *recordingTB) Cleanup(fn func()) {
+	self.cleanups = append(self.cleanups, fn)
+}
+
+func (self *recordingTB) cleanup() {
+	for i := len(self.cleanups) - 1; i >= 0; i-- {
+		self.cleanups[i]()
+	}
+}
+
+func TestExpectNoErrorsPassesWhenClean(t *testing.T) {
+	logger := logs.NewLogger(logs.LogLevelDebug, io.Discard)
+	tb := &recordingTB{TB: t}
+	ExpectNoErrors(tb, logger)
+	_ = logger.Debug(0, "a", 1)
+	_ = logger.Info(0, "a", 1)
+	_ = logger.Keyword(0, "a", 1)
+	if len(tb.errors) != 0 {
+		t.Fatalf("records below Warn should pass, got %v", tb.errors)
+	}
+}
+
+func TestExpectNoErrorsFailsOnError(t *testing.T) {
+	logger := logs.NewLogger(logs.LogLevelDebug, io.Discard)
+	tb := &recordingTB{TB: t}
+	ExpectNoErrors(tb, logger)
+	_ = logger.Error(0, "a", 1)
+	if len(tb.errors) != 1 {
+		t.Fatalf("unexpected Error should fail the test, got %v", tb.errors)
+	}
+	_ = logger.Warn(0, "a", 1)
+	if len(tb.errors) != 2 {
+		t.Fatalf("unexpected Warn should fail the test, got %v", tb.errors)
+	}
+
+	// 测试结束后恢复原有钩子
+	tb.cleanup()
+	_ = logger.Error(0, "a", 1)
+	if len(tb.errors) != 2 || len(logger.Hooks()) != 0 {
+		t.Fatalf("hook should be removed after cleanup, errors %v", tb.errors)
+	}
+}
+
+func TestExpectNoErrorsAllow(t *testing.T) {
+	logger := logs.NewLogger(logs.LogLevelDebug, io.Discard)
+	tb := &recordingTB{TB: t}
+	guard := ExpectNoErrors(tb, logger)
+	guard.Allow(func(entry *logs.Entry) bool {
+		for _, field := range entry.Fields {
+			if field.Key == "expected" {
+				return true
+			}
+		}
+		return false
+	})
+	_ = logger.Error(0, "expected", true)
+	if len(tb.errors) != 0 {
+		t.Fatalf("allowed record should pass, got %v", tb.errors)
+	}
+	_ = logger.Error(0, "a", 1)
+	if len(tb.errors) != 1 {
+		t.Fatalf("record not allowed should fail, got %v", tb.errors)
+	}
+}
@@ -0,0 +1,10 @@
+package logs
+
+// Must err不为nil时以Error等级输出异常，总是返回v，用于简化ErrorWith等返回(值, 异常)后的输出；
+// 方法不支持类型参数，因此为包级函数
+func Must[T any](logger *Logger, skip uint, v T, err error) T {
+	if err != nil {
+		_ = logger.ErrorError(skip+1, err)
+	}
+	return v
+}
@@ -0,0 +1,31 @@
+package logs
+
+import (
+	"errors"
+	"fmt"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+func TestMust(t *testing.T) {
+	logger, buf := newTestLogger(t, LogLevelDebug)
+	if v := Must(logger, 0, 5, nil); v != 5 {
+		t.Fatalf("want 5, got %d", v)
+	}
+	if buf.Len() != 0 {
+		t.Fatalf("nothing should be logged without an error: %q", buf.String())
+	}
+
+	_, file, line, _ := runtime.Caller(0)
+	if v := Must(logger, 0, "fallback", errors.New("boom")); v != "fallback" {
+		t.Fatalf("want the value even on error, got %q", v)
+	}
+	out := buf.String()
+	if !strings.Contains(out, " ERROR ") || !strings.HasSuffix(out, "| error=boom\n") {
+		t.Fatalf("error should be logged at Error: %q", out)
+	}
+	if pos := fmt.Sprintf("| %s:%d |", file, line+1); !strings.Contains(out, pos) {
+		t.Fatalf("position should be the Must call %q: %q", pos, out)
+	}
+}
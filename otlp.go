@@ -0,0 +1,210 @@
+package logs
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// otel日志的severity number
+var otlpSeverityMap = [...]int{
+	LogLevelDebug:   5,  // DEBUG
+	LogLevelInfo:    9,  // INFO
+	LogLevelWarn:    13, // WARN
+	LogLevelError:   17, // ERROR
+	LogLevelKeyword: 12, // INFO4
+}
+
+// otlp json格式的属性
+type otlpAttribute struct {
+	Key   string         `json:"key"`
+	Value map[string]any `json:"value"`
+}
+
+// otlp json格式的日志记录
+type otlpRecord struct {
+	TimeUnixNano   string          `json:"timeUnixNano"`
+	SeverityNumber int             `json:"severityNumber"`
+	SeverityText   string          `json:"severityText"`
+	Body           map[string]any  `json:"body"`
+	Attributes     []otlpAttribute `json:"attributes"`
+}
+
+// OTLPExporter 通过OTLP/HTTP（json编码）将记录导出为OpenTelemetry日志的导出器，
+// 使用AddHook(exporter.Export)接入日志管理器，记录在后台按批发送
+type OTLPExporter struct {
+	endpoint string
+	client   *http.Client
+	resource []otlpAttribute
+	records  chan otlpRecord
+	done     chan struct{}
+	doneLock sync.RWMutex // 保证done关闭后不再有记录入队
+	wg       sync.WaitGroup
+	once     sync.Once
+	lastErr  error
+	errLock  sync.Mutex
+}
+
+// NewOTLPExporter 新建OTLP导出器，endpoint如"http://localhost:4318/v1/logs"，resource为资源属性键值对
+func NewOTLPExporter(endpoint string, resource ...any) *OTLPExporter {
+	exporter := &OTLPExporter{
+		endpoint: endpoint,
+		client:   &http.Client{Timeout: 10 * time.Second},
+		records:  make(chan otlpRecord, 1024),
+		done:     make(chan struct{}),
+	}
+	rangeItems(resource, func(key string, value any) {
+		exporter.resource = append(exporter.resource, otlpAttribute{Key: key, Value: otlpValue(value)})
+	})
+	exporter.wg.Add(1)
+	go exporter.run()
+	return exporter
+}
+
+// 转换为otlp的AnyValue
+func otlpValue(v any) map[string]any {
+	switch v := v.(type) {
+	case string:
+		return map[string]any{"stringValue": v}
+	case bool:
+		return map[string]any{"boolValue": v}
+	case int, int8, int16, int32, int64, uint8, uint16, uint32:
+		return map[string]any{"intValue": fmt.Sprintf("%d", v)}
+	case uint:
+		return otlpUintValue(uint64(v))
+	case uint64:
+		return otlpUintValue(v)
+	case uintptr:
+		return otlpUintValue(uint64(v))
+	case float32, float64:
+		return map[string]any{"doubleValue": v}
+	default:
+		return map[string]any{"stringValue": formatValue(v)}
+	}
+}
+
+// otlp的intValue为int64，超出范围的无符号整数以字符串输出
+func otlpUintValue(v uint64) map[string]any {
+	if v > math.MaxInt64 {
+		return map[string]any{"stringValue": strconv.FormatUint(v, 10)}
+	}
+	return map[string]any{"intValue": strconv.FormatUint(v, 10)}
+}
+
+// Export 导出一条记录，可直接作为Hook使用，队列已满或已关闭时丢弃记录
+func (self *OTLPExporter) Export(entry *Entry) {
+	self.doneLock.RLock()
+	defer self.doneLock.RUnlock()
+	select {
+	case <-self.done:
+		return
+	default:
+	}
+
+	record := otlpRecord{
+		TimeUnixNano: strconv.FormatInt(entry.Time.UnixNano(), 10),
+		SeverityText: entry.Level.String(),
+		Body:         map[string]any{"stringValue": ""},
+		Attributes:   make([]otlpAttribute, 0, len(entry.Globals)+len(entry.Fields)+1),
+	}
+	if int(entry.Level) < len(otlpSeverityMap) {
+		record.SeverityNumber = otlpSeverityMap[entry.Level]
+	}
+	record.Attributes = append(record.Attributes, otlpAttribute{Key: "code.location", Value: otlpValue(entry.Caller)})
+	for _, field := range entry.Globals {
+		record.Attributes = append(record.Attributes, otlpAttribute{Key: field.Key, Value: otlpValue(field.Value)})
+	}
+	for _, field := range entry.Fields {
+		if field.Key == "msg" {
+			record.Body = otlpValue(field.Value)
+		} else {
+			record.Attributes = append(record.Attributes, otlpAttribute{Key: field.Key, Value: otlpValue(field.Value)})
+		}
+	}
+
+	select {
+	case self.records <- record:
+	default:
+	}
+}
+
+// 后台批量发送
+func (self *OTLPExporter) run() {
+	defer self.wg.Done()
+
+	ticker := time.NewTicker(time.Second)
+	defer ticker.Stop()
+
+	var batch []otlpRecord
+	for {
+		select {
+		case record := <-self.records:
+			if batch = append(batch, record); len(batch) >= 128 {
+				self.send(batch)
+				batch = nil
+			}
+		case <-ticker.C:
+			self.send(batch)
+			batch = nil
+		case <-self.done:
+			for {
+				select {
+				case record := <-self.records:
+					batch = append(batch, record)
+				default:
+					self.send(batch)
+					return
+				}
+			}
+		}
+	}
+}
+
+// 发送一批记录
+func (self *OTLPExporter) send(batch []otlpRecord) {
+	if len(batch) == 0 {
+		return
+	}
+	body, err := json.Marshal(map[string]any{
+		"resourceLogs": []any{map[string]any{
+			"resource": map[string]any{"attributes": self.resource},
+			"scopeLogs": []any{map[string]any{
+				"scope":      map[string]any{"name": pkgPath},
+				"logRecords": batch,
+			}},
+		}},
+	})
+	if err == nil {
+		var resp *http.Response
+		resp, err = self.client.Post(self.endpoint, "application/json", bytes.NewReader(body))
+		if err == nil {
+			_ = resp.Body.Close()
+			if resp.StatusCode/100 != 2 {
+				err = fmt.Errorf("otlp export failed with status %s", resp.Status)
+			}
+		}
+	}
+	if err != nil {
+		self.errLock.Lock()
+		self.lastErr = err
+		self.errLock.Unlock()
+	}
+}
+
+// Close 发送剩余的记录并停止导出，返回最近一次发送失败的异常
+func (self *OTLPExporter) Close() error {
+	self.once.Do(func() {
+		self.doneLock.Lock()
+		defer self.doneLock.Unlock()
+		close(self.done)
+	})
+	self.wg.Wait()
+	self.errLock.Lock()
+	defer self.errLock.Unlock()
+	return self.lastErr
+}
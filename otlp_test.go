@@ -0,0 +1,104 @@
+package logs
+
+import (
+	"encoding/json"
+	"io"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+)
+
+// 模拟的otlp收集器，记录收到的日志记录
+type fakeCollector struct {
+	lock    sync.Mutex
+	records []map[string]any
+}
+
+func (self *fakeCollector) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	var body struct {
+		ResourceLogs []struct {
+			ScopeLogs []struct {
+				LogRecords []map[string]any `json:"logRecords"`
+			} `json:"scopeLogs"`
+		} `json:"resourceLogs"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	self.lock.Lock()
+	defer self.lock.Unlock()
+	for _, resource := range body.ResourceLogs {
+		for _, scope := range resource.ScopeLogs {
+			self.records = append(self.records, scope.LogRecords...)
+		}
+	}
+}
+
+// 按键查找记录的属性值
+func otlpAttr(t *testing.T, record map[string]any, key string) map[string]any {
+	t.Helper()
+	attrs, _ := record["attributes"].([]any)
+	for _, attr := range attrs {
+		if attr, _ := attr.(map[string]any); attr["key"] == key {
+			value, _ := attr["value"].(map[string]any)
+			return value
+		}
+	}
+	t.Fatalf("attribute %q not found in %v", key, record)
+	return nil
+}
+
+func TestOTLPExporter(t *testing.T) {
+	collector := &fakeCollector{}
+	server := httptest.NewServer(collector)
+	defer server.Close()
+
+	exporter := NewOTLPExporter(server.URL+"/v1/logs", "service.name", "svc")
+	logger := NewLogger(LogLevelDebug, io.Discard)
+	logger.AddHook(exporter.Export)
+	_ = logger.Warnf(0, "hi")
+	_ = logger.Error(0, "k", 3, "u", uint64(7), "p", uintptr(8), "big", uint64(math.MaxUint64))
+	if err := exporter.Close(); err != nil {
+		t.Fatalf("close: %v", err)
+	}
+
+	collector.lock.Lock()
+	defer collector.lock.Unlock()
+	if len(collector.records) != 2 {
+		t.Fatalf("want 2 records, got %d", len(collector.records))
+	}
+	warn, errRecord := collector.records[0], collector.records[1]
+	if warn["severityNumber"] != float64(13) || warn["severityText"] != "WARN" {
+		t.Fatalf("unexpected warn severity: %v", warn)
+	}
+	if body, _ := warn["body"].(map[string]any); body["stringValue"] != "hi" {
+		t.Fatalf("msg should be the record body: %v", warn["body"])
+	}
+	if errRecord["severityNumber"] != float64(17) {
+		t.Fatalf("unexpected error severity: %v", errRecord)
+	}
+	for key, want := range map[string]string{"k": "3", "u": "7", "p": "8"} {
+		if got := otlpAttr(t, errRecord, key)["intValue"]; got != want {
+			t.Fatalf("attribute %q: want intValue %q, got %v", key, want, got)
+		}
+	}
+	if got := otlpAttr(t, errRecord, "big")["stringValue"]; got != "18446744073709551615" {
+		t.Fatalf("out of range uint64 should be a string, got %v", got)
+	}
+}
+
+func TestOTLPExporterDropsAfterClose(t *testing.T) {
+	collector := &fakeCollector{}
+	server := httptest.NewServer(collector)
+	defer server.Close()
+
+	exporter := NewOTLPExporter(server.URL + "/v1/logs")
+	_ = exporter.Close()
+	exporter.Export(&Entry{Level: LogLevelInfo})
+	if n := len(exporter.records); n != 0 {
+		t.Fatalf("records exported after close should be dropped, %d queued", n)
+	}
+}
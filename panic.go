@@ -0,0 +1,13 @@
+package logs
+
+// InstallPanicHandler 返回用于defer的panic处理函数，发生panic时以Error等级输出带栈异常并刷新写入器，然后继续panic，
+// 用法：defer logs.InstallPanicHandler(logger)()
+func InstallPanicHandler(logger *Logger) func() {
+	return func() {
+		if r := recover(); r != nil {
+			_ = logger.ErrorError(0, FromRecover(r))
+			_ = logger.Flush()
+			panic(r)
+		}
+	}
+}
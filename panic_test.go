@@ -0,0 +1,55 @@
+package logs
+
+import (
+	"fmt"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+// 在安装了panic处理函数的函数中越界访问，site记录发生panic的位置
+func panicWithHandler(logger *Logger, site *string) {
+	defer InstallPanicHandler(logger)()
+	var a []int
+	_, file, line, _ := runtime.Caller(0)
+	*site = fmt.Sprintf("%s:%d", file, line+3)
+	i := 3
+	_ = a[i]
+}
+
+func TestInstallPanicHandler(t *testing.T) {
+	logger, buf := newTestLogger(t, LogLevelDebug)
+	var site string
+	var recovered any
+	func() {
+		defer func() { recovered = recover() }()
+		panicWithHandler(logger, &site)
+	}()
+	if recovered == nil {
+		t.Fatal("panic should propagate after being logged")
+	}
+	if _, ok := recovered.(runtime.Error); !ok {
+		t.Fatalf("original panic value should be re-panicked, got %T", recovered)
+	}
+
+	out := buf.String()
+	if !strings.Contains(out, " ERROR ") || !strings.Contains(out, "error=panic: runtime error: index out of range") {
+		t.Fatalf("panic should be logged at Error: %q", out)
+	}
+	if !strings.Contains(out, "| "+site+" |") {
+		t.Fatalf("position should be the panic site %s: %q", site, out)
+	}
+	if !strings.Contains(out, "stack=\n\t") {
+		t.Fatalf("panic should be logged with a stack: %q", out)
+	}
+}
+
+func TestInstallPanicHandlerWithoutPanic(t *testing.T) {
+	logger, buf := newTestLogger(t, LogLevelDebug)
+	func() {
+		defer InstallPanicHandler(logger)()
+	}()
+	if buf.Len() != 0 {
+		t.Fatalf("nothing should be logged without a panic: %q", buf.String())
+	}
+}
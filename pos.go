@@ -0,0 +1,30 @@
+package logs
+
+// 以指定位置打印，不获取调用栈
+func (self *Logger) printAtPos(level LogLevel, pos string, a ...any) error {
+	items := self.checkItems(a...)
+	if !self.enabled(level) {
+		return nil
+	}
+	return self.output(level, pos, items)
+}
+
+// InfoAtPos 输出Info信息，位置原样使用pos（如配置文件中的行）
+func (self *Logger) InfoAtPos(pos string, a ...any) error {
+	return self.printAtPos(LogLevelInfo, pos, a...)
+}
+
+// WarnAtPos 输出Warn信息，位置原样使用pos（如配置文件中的行）
+func (self *Logger) WarnAtPos(pos string, a ...any) error {
+	return self.printAtPos(LogLevelWarn, pos, a...)
+}
+
+// ErrorAtPos 输出Error信息，位置原样使用pos（如配置文件中的行）
+func (self *Logger) ErrorAtPos(pos string, a ...any) error {
+	return self.printAtPos(LogLevelError, pos, a...)
+}
+
+// KeywordAtPos 输出Keyword信息，位置原样使用pos（如配置文件中的行）
+func (self *Logger) KeywordAtPos(pos string, a ...any) error {
+	return self.printAtPos(LogLevelKeyword, pos, a...)
+}
@@ -0,0 +1,25 @@
+package logs
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestAtPos(t *testing.T) {
+	logger, buf := newTestLogger(t, LogLevelInfo)
+	_ = logger.InfoAtPos("config.yaml:12", "msg", "x")
+	if !strings.Contains(buf.String(), "| config.yaml:12 | ") {
+		t.Fatalf("position should be rendered verbatim: %q", buf.String())
+	}
+	if strings.Contains(buf.String(), "pos_test.go") {
+		t.Fatalf("go call site should not be used: %q", buf.String())
+	}
+
+	// JSON格式同样原样输出
+	buf.Reset()
+	logger.SetFormatter(&JSONFormatter{})
+	_ = logger.ErrorAtPos("rules.dsl:3:7", "msg", "x")
+	if m := decodeJSON(t, strings.TrimSpace(buf.String())); m["caller"] != "rules.dsl:3:7" {
+		t.Fatalf("want caller rules.dsl:3:7, got %v", m["caller"])
+	}
+}
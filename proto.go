@@ -0,0 +1,61 @@
+package logs
+
+import (
+	"encoding/json"
+	"reflect"
+	"sync/atomic"
+)
+
+// ProtoMessage protobuf消息，protoc-gen-go生成的消息类型均实现了该接口
+type ProtoMessage interface {
+	ProtoMessage()
+	String() string
+}
+
+// ProtoMarshaler 将protobuf消息序列化为json，通常包装protojson.Marshal，如
+// func(m logs.ProtoMessage) ([]byte, error) { return protojson.Marshal(m.(proto.Message)) }
+type ProtoMarshaler func(msg ProtoMessage) ([]byte, error)
+
+var protoMarshaler atomic.Value
+
+// SetProtoMarshaler 设置json格式下protobuf消息的序列化函数，传入nil则恢复默认
+func SetProtoMarshaler(marshaler ProtoMarshaler) {
+	protoMarshaler.Store(marshaler)
+}
+
+// protobuf消息字段值
+type protoValue struct {
+	msg ProtoMessage
+}
+
+// Proto protobuf消息字段，文本格式使用消息的String()；json格式使用SetProtoMarshaler设置的序列化函数，
+// 未设置时输出String()的字符串，本包不依赖protobuf，encoding/json无法正确处理oneof、枚举及知名类型
+func Proto(key string, msg ProtoMessage) Field {
+	return Field{Key: key, Value: protoValue{msg: msg}}
+}
+
+// 消息是否为空
+func (self protoValue) isNil() bool {
+	if self.msg == nil {
+		return true
+	}
+	v := reflect.ValueOf(self.msg)
+	return v.Kind() == reflect.Ptr && v.IsNil()
+}
+
+func (self protoValue) String() string {
+	if self.isNil() {
+		return "<nil>"
+	}
+	return self.msg.String()
+}
+
+func (self protoValue) MarshalJSON() ([]byte, error) {
+	if self.isNil() {
+		return []byte("null"), nil
+	}
+	if marshaler, _ := protoMarshaler.Load().(ProtoMarshaler); marshaler != nil {
+		return marshaler(self.msg)
+	}
+	return json.Marshal(self.msg.String())
+}
@@ -0,0 +1,55 @@
+package logs
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+// 模拟protoc-gen-go生成的消息
+type fakeProtoMessage struct {
+	Name string
+	Id   int32
+}
+
+func (*fakeProtoMessage) ProtoMessage() {}
+
+func (self *fakeProtoMessage) String() string {
+	return fmt.Sprintf("name:%q id:%d", self.Name, self.Id)
+}
+
+func TestProtoText(t *testing.T) {
+	logger, buf := newTestLogger(t, LogLevelDebug)
+	_ = logger.Info(0, Proto("req", &fakeProtoMessage{Name: "a", Id: 3}), Proto("nil", (*fakeProtoMessage)(nil)))
+	if want := `req=name:"a" id:3 nil=<nil>`; !strings.Contains(buf.String(), want) {
+		t.Fatalf("want %q in %q", want, buf.String())
+	}
+}
+
+func TestProtoJSON(t *testing.T) {
+	defer SetProtoMarshaler(nil)
+	logger, buf := newTestLogger(t, LogLevelDebug)
+	logger.SetFormatter(&JSONFormatter{})
+
+	// 未设置序列化函数时输出文本格式的字符串
+	_ = logger.Info(0, Proto("req", &fakeProtoMessage{Name: "a", Id: 3}), Proto("nil", (*fakeProtoMessage)(nil)))
+	m := decodeJSON(t, strings.TrimSpace(buf.String()))
+	if m["req"] != `name:"a" id:3` || m["nil"] != nil {
+		t.Fatalf("unexpected default output: %s", buf.String())
+	}
+
+	// 模拟protojson，输出带空白的json
+	SetProtoMarshaler(func(msg ProtoMessage) ([]byte, error) {
+		fake := msg.(*fakeProtoMessage)
+		return []byte(fmt.Sprintf(`{"name": "%s",  "id": %d}`, fake.Name, fake.Id)), nil
+	})
+	buf.Reset()
+	_ = logger.Info(0, Proto("req", &fakeProtoMessage{Name: "a", Id: 3}), Proto("nil", (*fakeProtoMessage)(nil)))
+	if !json.Valid(buf.Bytes()) {
+		t.Fatalf("output should be valid json: %s", buf.String())
+	}
+	if want := `"req":{"name":"a","id":3},"nil":null`; !strings.Contains(buf.String(), want) {
+		t.Fatalf("want %s in %s", want, buf.String())
+	}
+}
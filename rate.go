@@ -0,0 +1,46 @@
+package logs
+
+import (
+	"strconv"
+	"sync"
+	"time"
+)
+
+// 计数器上次记录的状态
+type rateState struct {
+	count uint64
+	time  time.Time
+}
+
+var (
+	rateLock   sync.Mutex
+	rateStates = make(map[string]rateState)
+)
+
+// 每秒速率
+type rateValue float64
+
+// Rate 速率字段，count为key对应计数器的累计值，值为距上次以同一key调用时的每秒增量，首次调用为0，
+// 文本格式为"12.50/s"，json格式为数值
+func Rate(key string, count uint64) Field {
+	t := now()
+
+	rateLock.Lock()
+	last, ok := rateStates[key]
+	rateStates[key] = rateState{count: count, time: t}
+	rateLock.Unlock()
+
+	var rate rateValue
+	if elapsed := t.Sub(last.time).Seconds(); ok && elapsed > 0 && count >= last.count {
+		rate = rateValue(float64(count-last.count) / elapsed)
+	}
+	return Field{Key: key, Value: rate}
+}
+
+func (self rateValue) String() string {
+	return strconv.FormatFloat(float64(self), 'f', 2, 64) + "/s"
+}
+
+func (self rateValue) MarshalJSON() ([]byte, error) {
+	return []byte(strconv.FormatFloat(float64(self), 'f', -1, 64)), nil
+}
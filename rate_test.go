@@ -0,0 +1,48 @@
+package logs
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRate(t *testing.T) {
+	base := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	defer func(fn func() time.Time) { now = fn }(now)
+	current := base
+	now = func() time.Time { return current }
+	defer func() {
+		rateLock.Lock()
+		defer rateLock.Unlock()
+		delete(rateStates, "test.reqs")
+	}()
+
+	logger, buf := newTestLogger(t, LogLevelDebug)
+	_ = logger.Info(0, Rate("test.reqs", 100))
+	if !strings.HasSuffix(buf.String(), "| test.reqs=0.00/s\n") {
+		t.Fatalf("first call should report 0: %q", buf.String())
+	}
+
+	buf.Reset()
+	current = base.Add(2 * time.Second)
+	_ = logger.Info(0, Rate("test.reqs", 125))
+	if !strings.HasSuffix(buf.String(), "| test.reqs=12.50/s\n") {
+		t.Fatalf("want 25 over 2s: %q", buf.String())
+	}
+
+	// 计数器重置时不输出负速率
+	buf.Reset()
+	current = base.Add(3 * time.Second)
+	_ = logger.Info(0, Rate("test.reqs", 10))
+	if !strings.HasSuffix(buf.String(), "| test.reqs=0.00/s\n") {
+		t.Fatalf("counter reset should report 0: %q", buf.String())
+	}
+
+	buf.Reset()
+	logger.SetFormatter(&JSONFormatter{})
+	current = base.Add(5 * time.Second)
+	_ = logger.Info(0, Rate("test.reqs", 20))
+	if m := decodeJSON(t, strings.TrimSpace(buf.String())); m["test.reqs"] != float64(5) {
+		t.Fatalf("json rate should be numeric 5, got %v", m["test.reqs"])
+	}
+}
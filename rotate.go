@@ -0,0 +1,130 @@
+package logs
+
+import (
+	"io"
+	"os"
+	"sync"
+)
+
+// RotateWriter 按大小轮转的文件写入器，文件超过maxSize字节时重命名为带时间后缀的文件并新建文件
+type RotateWriter struct {
+	lock    sync.Mutex
+	path    string
+	maxSize int64
+	file    *os.File // 轮转后重新打开失败时为空
+	size    int64
+	closed  bool // 是否已关闭
+}
+
+// NewRotateWriter 新建按大小轮转的文件写入器，maxSize为0表示不轮转
+func NewRotateWriter(path string, maxSize int64) (*RotateWriter, error) {
+	w := &RotateWriter{
+		path:    path,
+		maxSize: maxSize,
+	}
+	if err := w.open(); err != nil {
+		return nil, err
+	}
+	return w, nil
+}
+
+// 打开文件
+func (self *RotateWriter) open() error {
+	file, err := os.OpenFile(self.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return err
+	}
+	info, err := file.Stat()
+	if err != nil {
+		_ = file.Close()
+		return err
+	}
+	self.file, self.size = file, info.Size()
+	return nil
+}
+
+// 轮转文件，重命名失败时重新打开原文件继续使用，打开失败时在下次写入时重试
+func (self *RotateWriter) rotate() error {
+	if err := self.file.Close(); err != nil {
+		return err
+	}
+	self.file = nil
+	renameErr := os.Rename(self.path, self.path+"."+now().Format("20060102150405.000000"))
+	if err := self.open(); err != nil {
+		return err
+	}
+	return renameErr
+}
+
+func (self *RotateWriter) Write(p []byte) (int, error) {
+	self.lock.Lock()
+	defer self.lock.Unlock()
+
+	if self.closed {
+		return 0, os.ErrClosed
+	}
+	if self.file == nil {
+		if err := self.open(); err != nil {
+			return 0, err
+		}
+	}
+	if self.maxSize > 0 && self.size > 0 && self.size+int64(len(p)) > self.maxSize {
+		if err := self.rotate(); err != nil {
+			return 0, err
+		}
+	}
+	n, err := self.file.Write(p)
+	self.size += int64(n)
+	return n, err
+}
+
+// Sync 落盘
+func (self *RotateWriter) Sync() error {
+	self.lock.Lock()
+	defer self.lock.Unlock()
+	if self.file == nil {
+		return nil
+	}
+	return self.file.Sync()
+}
+
+// Close 关闭文件
+func (self *RotateWriter) Close() error {
+	self.lock.Lock()
+	defer self.lock.Unlock()
+	self.closed = true
+	if self.file == nil {
+		return nil
+	}
+	return self.file.Close()
+}
+
+// NewFileLogger 新建输出到文件的日志管理器，全部记录写入path，Warn及以上的记录同时写入errPath，两者均按maxSize字节轮转，
+// 返回的io.Closer用于关闭两个文件
+func NewFileLogger(level LogLevel, path, errPath string, maxSize int64, values ...any) (*Logger, io.Closer, error) {
+	writer, err := NewRotateWriter(path, maxSize)
+	if err != nil {
+		return nil, nil, err
+	}
+	errWriter, err := NewRotateWriter(errPath, maxSize)
+	if err != nil {
+		_ = writer.Close()
+		return nil, nil, err
+	}
+	logger := NewLogger(level, writer, values...)
+	logger.AddWriter(LogLevelWarn, errWriter)
+	return logger, multiCloser{writer, errWriter}, nil
+}
+
+// 依次关闭的多个io.Closer，返回第一个异常
+type multiCloser []io.Closer
+
+func (self multiCloser) Close() error {
+	var err error
+	for _, c := range self {
+		if e := c.Close(); err == nil {
+			err = e
+		}
+	}
+	return err
+}
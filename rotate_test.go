@@ -0,0 +1,137 @@
+package logs
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// 读取文件内容
+func readFile(t *testing.T, path string) string {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(data)
+}
+
+func TestFileLoggerSplitsByLevel(t *testing.T) {
+	dir := t.TempDir()
+	path, errPath := filepath.Join(dir, "app.log"), filepath.Join(dir, "error.log")
+	logger, closer, err := NewFileLogger(LogLevelDebug, path, errPath, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer closer.Close()
+	_ = logger.Info(0, "k", "info-record")
+	_ = logger.Warn(0, "k", "warn-record")
+	if err = logger.Flush(); err != nil {
+		t.Fatal(err)
+	}
+
+	app, errLog := readFile(t, path), readFile(t, errPath)
+	if !strings.Contains(app, "info-record") || !strings.Contains(app, "warn-record") {
+		t.Fatalf("main file should contain all records: %q", app)
+	}
+	if strings.Contains(errLog, "info-record") || !strings.Contains(errLog, "warn-record") {
+		t.Fatalf("error file should only contain warn and above: %q", errLog)
+	}
+}
+
+func TestRotateWriter(t *testing.T) {
+	dir := t.TempDir()
+	w, err := NewRotateWriter(filepath.Join(dir, "app.log"), 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer w.Close()
+	for i := 0; i < 3; i++ {
+		if _, err = w.Write([]byte("12345678\n")); err != nil {
+			t.Fatal(err)
+		}
+	}
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 3 {
+		t.Fatalf("want the current file plus two rotated files, got %d", len(entries))
+	}
+}
+
+func TestRotateWriterRecoversFromRenameFailure(t *testing.T) {
+	stamp := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	now = func() time.Time { return stamp }
+	defer func() { now = time.Now }()
+
+	dir := t.TempDir()
+	path := filepath.Join(dir, "app.log")
+	w, err := NewRotateWriter(path, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer w.Close()
+	if _, err = w.Write([]byte("12345678\n")); err != nil {
+		t.Fatal(err)
+	}
+
+	// 轮转的目标路径被非空目录占用，重命名失败
+	backup := path + "." + stamp.Format("20060102150405.000000")
+	if err = os.MkdirAll(filepath.Join(backup, "x"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if _, err = w.Write([]byte("abcdefgh\n")); err == nil {
+		t.Fatal("rotation should fail while the backup path is a directory")
+	}
+
+	// 目标路径恢复可用后继续轮转及写入
+	if err = os.RemoveAll(backup); err != nil {
+		t.Fatal(err)
+	}
+	if _, err = w.Write([]byte("abcdefgh\n")); err != nil {
+		t.Fatalf("writer should recover after a failed rotation: %v", err)
+	}
+	if got := readFile(t, path); got != "abcdefgh\n" {
+		t.Fatalf("current file should hold the new record: %q", got)
+	}
+	if got := readFile(t, backup); got != "12345678\n" {
+		t.Fatalf("rotated file should hold the old record: %q", got)
+	}
+}
+
+func TestRotateWriterClosed(t *testing.T) {
+	w, err := NewRotateWriter(filepath.Join(t.TempDir(), "app.log"), 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err = w.Write([]byte("x\n")); !errors.Is(err, os.ErrClosed) {
+		t.Fatalf("write after close should fail with os.ErrClosed, got %v", err)
+	}
+}
+
+func TestFileLoggerClose(t *testing.T) {
+	dir := t.TempDir()
+	logger, closer, err := NewFileLogger(LogLevelDebug, filepath.Join(dir, "app.log"), filepath.Join(dir, "error.log"), 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = closer.Close(); err != nil {
+		t.Fatal(err)
+	}
+	// 关闭后两个文件均不可再写入
+	if err = logger.Info(0, "k", "v"); !errors.Is(err, os.ErrClosed) {
+		t.Fatalf("main file should be closed, got %v", err)
+	}
+	for _, c := range closer.(multiCloser) {
+		if !c.(*RotateWriter).closed {
+			t.Fatalf("%s should be closed", c.(*RotateWriter).path)
+		}
+	}
+}
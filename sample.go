@@ -0,0 +1,39 @@
+package logs
+
+import (
+	"fmt"
+	"sync/atomic"
+)
+
+// 采样器，按比例均匀保留记录
+type sampler struct {
+	rate  float64
+	count uint64
+}
+
+// SetSampleRate 设置指定等级的采样比例，rate取值(0, 1)时按比例均匀保留该等级的记录（如0.25表示每4条保留1条），
+// 不大于0时丢弃全部记录，不小于1时不采样；子日志管理器与父日志管理器共享已有的采样计数
+func (self *Logger) SetSampleRate(level LogLevel, rate float64) error {
+	if int(level) >= len(self.samplers) {
+		return fmt.Errorf("unknown log level `%d`", level)
+	}
+	if rate >= 1 {
+		self.samplers[level] = nil
+		return nil
+	}
+	self.samplers[level] = &sampler{rate: rate}
+	return nil
+}
+
+// 记录是否被采样保留
+func (self *Logger) sampled(level LogLevel) bool {
+	s := self.samplers[level]
+	if s == nil {
+		return true
+	}
+	if s.rate <= 0 {
+		return false
+	}
+	n := atomic.AddUint64(&s.count, 1)
+	return uint64(float64(n)*s.rate) != uint64(float64(n-1)*s.rate)
+}
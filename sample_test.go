@@ -0,0 +1,59 @@
+package logs
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestSetSampleRate(t *testing.T) {
+	logger, buf := newTestLogger(t, LogLevelDebug)
+	logger.SetSampleRate(LogLevelInfo, 0.25)
+	for i := 0; i < 100; i++ {
+		_ = logger.Info(0, "i", i)
+		_ = logger.Error(0, "i", i)
+	}
+	out := buf.String()
+	if n := strings.Count(out, " INFO "); n != 25 {
+		t.Fatalf("want 25 of 100 info records at rate 0.25, got %d", n)
+	}
+	if n := strings.Count(out, " ERROR "); n != 100 {
+		t.Fatalf("error records should never be sampled, got %d of 100", n)
+	}
+	// 均匀保留，而非只保留前25条
+	if !strings.Contains(out, "i=99") {
+		t.Fatalf("sampling should be spread evenly: %q", out)
+	}
+}
+
+func TestSetSampleRateBounds(t *testing.T) {
+	logger, buf := newTestLogger(t, LogLevelDebug)
+	logger.SetSampleRate(LogLevelInfo, 0)
+	_ = logger.Info(0, "a", 1)
+	if buf.Len() != 0 {
+		t.Fatalf("rate 0 should drop all records: %q", buf.String())
+	}
+
+	logger.SetSampleRate(LogLevelInfo, 1)
+	for i := 0; i < 10; i++ {
+		_ = logger.Info(0, "a", 1)
+	}
+	if n := strings.Count(buf.String(), "\n"); n != 10 {
+		t.Fatalf("rate 1 should keep all records, got %d of 10", n)
+	}
+	if err := logger.SetSampleRate(LogLevel(7), 0.5); err == nil {
+		t.Fatal("want error for unknown level")
+	}
+}
+
+func TestSampleRateSharedWithChild(t *testing.T) {
+	logger, buf := newTestLogger(t, LogLevelDebug)
+	logger.SetSampleRate(LogLevelInfo, 0.5)
+	child := logger.NewGroup()
+	for i := 0; i < 10; i++ {
+		_ = logger.Info(0, "a", 1)
+		_ = child.Info(0, "a", 1)
+	}
+	if n := strings.Count(buf.String(), "\n"); n != 10 {
+		t.Fatalf("parent and child should share the sample count, kept %d of 20", n)
+	}
+}
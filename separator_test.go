@@ -0,0 +1,56 @@
+package logs
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/gookit/color"
+)
+
+func TestSeparator(t *testing.T) {
+	t.Setenv("CLICOLOR_FORCE", "1")
+	logger, buf := newTestLogger(t, LogLevelDebug)
+	var extra bytes.Buffer
+	logger.AddWriter(LogLevelDebug, &extra)
+	if err := logger.Separator(); err != nil {
+		t.Fatalf("separator: %v", err)
+	}
+	if !strings.Contains(buf.String(), color.Gray.String()) {
+		t.Fatalf("separator should be painted gray: %q", buf.String())
+	}
+	if line := strings.TrimSpace(color.ClearCode(buf.String())); line != strings.Repeat("─", defaultSeparatorWidth) {
+		t.Fatalf("want %d dashes, got %q", defaultSeparatorWidth, line)
+	}
+	// 附加写入器不输出
+	if extra.Len() != 0 {
+		t.Fatalf("additional writers should not get the separator: %q", extra.String())
+	}
+
+	// 宽度跟随SetMaxWidth
+	buf.Reset()
+	logger.SetMaxWidth(20)
+	_ = logger.Separator()
+	if line := strings.TrimSpace(color.ClearCode(buf.String())); line != strings.Repeat("─", 20) {
+		t.Fatalf("want 20 dashes, got %q", line)
+	}
+}
+
+func TestSeparatorSkipped(t *testing.T) {
+	t.Setenv("CLICOLOR_FORCE", "")
+	t.Setenv("NO_COLOR", "")
+	t.Setenv("CLICOLOR", "")
+	logger, buf := newTestLogger(t, LogLevelDebug)
+	_ = logger.Separator()
+	if buf.Len() != 0 {
+		t.Fatalf("non-terminal writer should not get the separator: %q", buf.String())
+	}
+
+	t.Setenv("CLICOLOR_FORCE", "1")
+	logger, buf = newTestLogger(t, LogLevelDebug)
+	logger.SetFormatter(&JSONFormatter{})
+	_ = logger.Separator()
+	if buf.Len() != 0 {
+		t.Fatalf("formatted logger should not write the separator: %q", buf.String())
+	}
+}
@@ -0,0 +1,17 @@
+package logs
+
+// LogShutdown 以Info等级输出关闭记录，包含运行时长及各等级已输出的记录数
+func LogShutdown(logger *Logger) error {
+	stats := logger.Stats()
+	counts := make([]Field, len(stats.Counts))
+	for i, count := range stats.Counts {
+		counts[i] = Field{Key: LogLevel(i).String(), Value: count}
+	}
+	return logger.Info(
+		1,
+		"msg", "shutdown",
+		"uptime", now().Sub(stats.Start),
+		"records", counts,
+		"dropped", stats.Dropped,
+	)
+}
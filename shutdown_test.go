@@ -0,0 +1,29 @@
+package logs
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestLogShutdown(t *testing.T) {
+	base := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	defer func(fn func() time.Time) { now = fn }(now)
+	now = func() time.Time { return base }
+
+	logger, buf := newTestLogger(t, LogLevelDebug)
+	_ = logger.Info(0, "a", 1)
+	// 子日志管理器的记录计入父日志管理器
+	_ = logger.NewGroup().Warn(0, "a", 1)
+
+	buf.Reset()
+	now = func() time.Time { return base.Add(time.Minute) }
+	_ = LogShutdown(logger)
+	want := "msg=shutdown uptime=1m0s records={DEBUG=0 INFO=1 WARN=1 ERROR=0 KEYWORD=0} dropped=0"
+	if !strings.Contains(buf.String(), want) {
+		t.Fatalf("want %q in %q", want, buf.String())
+	}
+	if !strings.Contains(buf.String(), "shutdown_test.go:") {
+		t.Fatalf("position should point to the caller of LogShutdown: %q", buf.String())
+	}
+}
@@ -0,0 +1,37 @@
+package logs
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// 读取源文件第line行前后context行的源码片段，读取失败时返回false
+func sourceSnippet(file string, line, context int) (string, bool) {
+	f, err := os.Open(file)
+	if err != nil {
+		return "", false
+	}
+	defer f.Close()
+
+	begin, end := line-context, line+context
+	width := len(fmt.Sprintf("%d", end))
+
+	var buf strings.Builder
+	scanner := bufio.NewScanner(f)
+	for i := 1; scanner.Scan() && i <= end; i++ {
+		if i < begin {
+			continue
+		}
+		mark := ' '
+		if i == line {
+			mark = '>'
+		}
+		buf.WriteString(fmt.Sprintf("\n\t%c %*d | %s", mark, width, i, scanner.Text()))
+	}
+	if scanner.Err() != nil || buf.Len() == 0 {
+		return "", false
+	}
+	return buf.String(), true
+}
@@ -0,0 +1,37 @@
+package logs
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+func TestSourceContext(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "main.go")
+	src := "package main\n\nfunc main() {\n\tpanic(\"boom\")\n}\n"
+	if err := os.WriteFile(path, []byte(src), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	logger, buf := newTestLogger(t, LogLevelDebug)
+	logger.SetSourceContext(1)
+	_ = logger.ErrorError(0, customError{stacks: []runtime.Frame{{File: path, Line: 4}}})
+	want := "source=\n\t  3 | func main() {\n\t> 4 | \tpanic(\"boom\")\n\t  5 | }"
+	if !strings.Contains(buf.String(), want) {
+		t.Fatalf("want snippet %q in %q", want, buf.String())
+	}
+}
+
+func TestSourceContextMissingFile(t *testing.T) {
+	logger, buf := newTestLogger(t, LogLevelDebug)
+	logger.SetSourceContext(1)
+	_ = logger.ErrorError(0, customError{stacks: []runtime.Frame{{File: "missing.go", Line: 3}}})
+	if strings.Contains(buf.String(), "source=") {
+		t.Fatalf("snippet should be skipped for a missing file: %q", buf.String())
+	}
+	if !strings.Contains(buf.String(), "error=custom") {
+		t.Fatalf("record should still be written: %q", buf.String())
+	}
+}
@@ -0,0 +1,134 @@
+package logs
+
+import (
+	"bytes"
+	"io"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+// 记录每次写入大小的写入器
+type countingWriter struct {
+	buf      bytes.Buffer
+	writes   int
+	maxWrite int
+}
+
+func (self *countingWriter) Write(p []byte) (int, error) {
+	self.writes++
+	if len(p) > self.maxWrite {
+		self.maxWrite = len(p)
+	}
+	return self.buf.Write(p)
+}
+
+func TestStreamChunkSize(t *testing.T) {
+	const chunk = 32 << 10
+	payload := strings.Repeat("x", 4<<20)
+
+	// 先以整条写入得到期望输出
+	wantBuf := &countingWriter{}
+	want := NewLogger(LogLevelDebug, wantBuf, "app", "x")
+	_ = want.Info(0, "payload", payload, "n", 1)
+
+	w := &countingWriter{}
+	logger := NewLogger(LogLevelDebug, w, "app", "x")
+	logger.SetStreamChunkSize(chunk)
+	_ = logger.Info(0, "payload", payload, "n", 1)
+
+	if w.maxWrite > chunk {
+		t.Fatalf("each write should be at most %d bytes, got %d", chunk, w.maxWrite)
+	}
+	if w.writes < len(payload)/chunk {
+		t.Fatalf("record should be written in chunks, got %d writes", w.writes)
+	}
+	out := w.buf.String()
+	if !strings.HasSuffix(out, "| [app]x | payload="+payload+" n=1\n") {
+		t.Fatalf("streamed record is malformed: %q...", out[:100])
+	}
+	// 与整条写入的输出一致（时间及调用位置除外）
+	exp := wantBuf.buf.String()
+	if out[strings.Index(out, "| [app]"):] != exp[strings.Index(exp, "| [app]"):] {
+		t.Fatal("streamed record should match the non-streamed rendering")
+	}
+}
+
+func TestStreamChunkSizeAvoidsRecordCopies(t *testing.T) {
+	const chunk = 32 << 10
+	payload := strings.Repeat("x", 8<<20)
+	// 写入器不保存内容，避免测量到写入器自身的内存
+	logger := NewLogger(LogLevelDebug, discardWriter{})
+	logger.SetStreamChunkSize(chunk)
+
+	var before, after runtime.MemStats
+	runtime.GC()
+	runtime.ReadMemStats(&before)
+	_ = logger.Info(0, "payload", payload)
+	runtime.ReadMemStats(&after)
+	if allocated := after.TotalAlloc - before.TotalAlloc; allocated > uint64(len(payload))/4 {
+		t.Fatalf("streaming an %d byte field allocated %d bytes", len(payload), allocated)
+	}
+
+	// 未开启时至少复制一次整条记录
+	logger.SetStreamChunkSize(0)
+	runtime.GC()
+	runtime.ReadMemStats(&before)
+	_ = logger.Info(0, "payload", payload)
+	runtime.ReadMemStats(&after)
+	if allocated := after.TotalAlloc - before.TotalAlloc; allocated < uint64(len(payload)) {
+		t.Fatalf("non-streamed record should be rendered into memory, allocated only %d bytes", allocated)
+	}
+}
+
+// 丢弃写入内容的写入器
+type discardWriter struct{}
+
+func (discardWriter) Write(p []byte) (int, error) { return len(p), nil }
+
+func TestStreamChunkSizeFallbacks(t *testing.T) {
+	payload := strings.Repeat("y", 100<<10)
+
+	// 格式化器及长度前缀分帧先完整渲染，但仍分块写入
+	for _, setup := range []func(*Logger){
+		func(logger *Logger) { logger.SetFormatter(&JSONFormatter{}) },
+		func(logger *Logger) { logger.SetFraming(FramingLengthPrefix) },
+	} {
+		w := &countingWriter{}
+		logger := NewLogger(LogLevelDebug, w)
+		logger.SetStreamChunkSize(4 << 10)
+		setup(logger)
+		_ = logger.Info(0, "payload", payload)
+		if w.maxWrite > 4<<10 || !strings.Contains(w.buf.String(), payload) {
+			t.Fatalf("fallback should still write in chunks, max write %d", w.maxWrite)
+		}
+	}
+
+	// 模板直接渲染到写入器
+	w := &countingWriter{}
+	logger := NewLogger(LogLevelDebug, w)
+	logger.SetStreamChunkSize(4 << 10)
+	if err := logger.SetTemplate(LogLevelInfo, `{{.Level}} {{range .Fields}}{{.Key}}={{.Value}}{{end}}`); err != nil {
+		t.Fatal(err)
+	}
+	_ = logger.Info(0, "payload", payload)
+	if w.maxWrite > 4<<10 || w.buf.String() != "INFO payload="+payload+"\n" {
+		t.Fatalf("template record should be streamed with a trailing newline, max write %d", w.maxWrite)
+	}
+}
+
+func TestStreamChunkSizeStderrFallback(t *testing.T) {
+	var captured bytes.Buffer
+	defer func(w io.Writer) { stderr = w }(stderr)
+	stderr = &captured
+
+	logger := NewLogger(LogLevelDebug, failingWriter{})
+	logger.SetStreamChunkSize(16)
+	logger.SetStderrFallback(true)
+	if err := logger.Info(0, "msg", "saved"); err == nil {
+		t.Fatal("write error should be returned")
+	}
+	if !strings.HasSuffix(captured.String(), "| msg=saved\n") {
+		t.Fatalf("streamed record should still fall back to stderr: %q", captured.String())
+	}
+}
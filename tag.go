@@ -0,0 +1,58 @@
+package logs
+
+import "sync"
+
+// 日志管理器标签
+type loggerTag struct {
+	key, value string
+}
+
+var (
+	tagLock    sync.Mutex
+	taggedLogs = make(map[loggerTag][]*Logger)
+)
+
+// Tag 为日志管理器打上标签（如"subsystem"="db"），供SetLevelByTag按标签批量调整等级，
+// 标签不会被子日志管理器继承，打上标签的日志管理器在调用Untag前会一直被持有
+func (self *Logger) Tag(key, value string) {
+	tag := loggerTag{key: key, value: value}
+	tagLock.Lock()
+	defer tagLock.Unlock()
+	for _, logger := range taggedLogs[tag] {
+		if logger == self {
+			return
+		}
+	}
+	taggedLogs[tag] = append(taggedLogs[tag], self)
+}
+
+// Untag 移除日志管理器的标签
+func (self *Logger) Untag(key, value string) {
+	tag := loggerTag{key: key, value: value}
+	tagLock.Lock()
+	defer tagLock.Unlock()
+	loggers := taggedLogs[tag]
+	for i, logger := range loggers {
+		if logger != self {
+			continue
+		}
+		loggers = append(loggers[:i:i], loggers[i+1:]...)
+		break
+	}
+	if len(loggers) == 0 {
+		delete(taggedLogs, tag)
+	} else {
+		taggedLogs[tag] = loggers
+	}
+}
+
+// SetLevelByTag 设置所有带有指定标签的日志管理器的等级，返回调整的日志管理器数量
+func SetLevelByTag(key, value string, level LogLevel) int {
+	tagLock.Lock()
+	defer tagLock.Unlock()
+	loggers := taggedLogs[loggerTag{key: key, value: value}]
+	for _, logger := range loggers {
+		logger.SetLevel(level)
+	}
+	return len(loggers)
+}
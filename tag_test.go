@@ -0,0 +1,31 @@
+package logs
+
+import (
+	"io"
+	"testing"
+)
+
+func TestSetLevelByTag(t *testing.T) {
+	db := NewLogger(LogLevelInfo, io.Discard)
+	http := NewLogger(LogLevelInfo, io.Discard)
+	db.Tag("test.subsystem", "db")
+	db.Tag("test.subsystem", "db")
+	http.Tag("test.subsystem", "http")
+	defer db.Untag("test.subsystem", "db")
+	defer http.Untag("test.subsystem", "http")
+
+	if n := SetLevelByTag("test.subsystem", "db", LogLevelDebug); n != 1 {
+		t.Fatalf("repeated tagging should register once, adjusted %d loggers", n)
+	}
+	if db.Level() != LogLevelDebug {
+		t.Fatalf("tagged logger should be bumped to debug, got %s", db.Level())
+	}
+	if http.Level() != LogLevelInfo {
+		t.Fatalf("logger with another tag value should be untouched, got %s", http.Level())
+	}
+
+	db.Untag("test.subsystem", "db")
+	if n := SetLevelByTag("test.subsystem", "db", LogLevelError); n != 0 || db.Level() != LogLevelDebug {
+		t.Fatalf("untagged logger should no longer be adjusted, adjusted %d loggers, level %s", n, db.Level())
+	}
+}
@@ -0,0 +1,10 @@
+//go:build linux
+
+package logs
+
+import "syscall"
+
+// 获取当前系统线程id
+func osThreadID() (int, bool) {
+	return syscall.Gettid(), true
+}
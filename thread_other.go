@@ -0,0 +1,8 @@
+//go:build !linux
+
+package logs
+
+// 获取当前系统线程id，非linux平台不支持
+func osThreadID() (int, bool) {
+	return 0, false
+}
@@ -0,0 +1,57 @@
+package logs
+
+import (
+	"bytes"
+	"strings"
+)
+
+// 字段校验异常
+type fieldError struct {
+	path, msg string
+}
+
+// 字段校验异常列表
+type fieldErrorsValue []fieldError
+
+// FieldErrors 字段校验异常字段，pairs为字段路径与异常信息交替排列，
+// 文本格式为"[user.email: invalid, user.age: too young]"，json格式为[{"field":"user.email","message":"invalid"}]
+func FieldErrors(pairs ...string) Field {
+	if len(pairs)%2 != 0 {
+		panic("The number of items needs to be an even number")
+	}
+	errs := make(fieldErrorsValue, 0, len(pairs)/2)
+	for i := 0; i < len(pairs); i += 2 {
+		errs = append(errs, fieldError{path: pairs[i], msg: pairs[i+1]})
+	}
+	return Field{Key: "field_errors", Value: errs}
+}
+
+func (self fieldErrorsValue) String() string {
+	var buf strings.Builder
+	buf.WriteByte('[')
+	for i, err := range self {
+		if i > 0 {
+			buf.WriteString(", ")
+		}
+		buf.WriteString(err.path)
+		buf.WriteString(": ")
+		buf.WriteString(err.msg)
+	}
+	buf.WriteByte(']')
+	return buf.String()
+}
+
+func (self fieldErrorsValue) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteByte('[')
+	for i, err := range self {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		if e := writeJSONObject(&buf, []Field{{Key: "field", Value: err.path}, {Key: "message", Value: err.msg}}); e != nil {
+			return nil, e
+		}
+	}
+	buf.WriteByte(']')
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,38 @@
+package logs
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestFieldErrors(t *testing.T) {
+	logger, buf := newTestLogger(t, LogLevelDebug)
+	_ = logger.Warn(0, "msg", "bad", FieldErrors("user.email", "invalid", "user.age", "too young"))
+	if want := "| msg=bad field_errors=[user.email: invalid, user.age: too young]\n"; !strings.HasSuffix(buf.String(), want) {
+		t.Fatalf("want suffix %q, got %q", want, buf.String())
+	}
+
+	buf.Reset()
+	logger.SetFormatter(&JSONFormatter{})
+	_ = logger.Warn(0, FieldErrors("user.email", "invalid", "user.age", "too young"))
+	m := decodeJSON(t, strings.TrimSpace(buf.String()))
+	errs, ok := m["field_errors"].([]any)
+	if !ok || len(errs) != 2 {
+		t.Fatalf("want a json array of 2 errors, got %v", m["field_errors"])
+	}
+	for i, want := range [][2]string{{"user.email", "invalid"}, {"user.age", "too young"}} {
+		err, _ := errs[i].(map[string]any)
+		if err["field"] != want[0] || err["message"] != want[1] {
+			t.Fatalf("error %d: want %v, got %v", i, want, errs[i])
+		}
+	}
+}
+
+func TestFieldErrorsOddPairs(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Fatal("odd number of items should panic")
+		}
+	}()
+	FieldErrors("user.email")
+}
@@ -0,0 +1,40 @@
+package logs
+
+import (
+	"os"
+	"sync"
+	"time"
+)
+
+// 等级文件的轮询间隔
+var levelFilePollInterval = time.Second
+
+// WatchLevelFile 轮询监听文件内容，内容为合法的日志等级名时将其设置为logger的等级，返回停止监听的函数
+func WatchLevelFile(logger *Logger, path string) (stop func()) {
+	done := make(chan struct{})
+	go func() {
+		ticker := time.NewTicker(levelFilePollInterval)
+		defer ticker.Stop()
+
+		var last string
+		for {
+			if data, err := os.ReadFile(path); err == nil && string(data) != last {
+				last = string(data)
+				if level, err := ParseLevel(last); err == nil {
+					logger.SetLevel(level)
+				}
+			}
+
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+
+	var once sync.Once
+	return func() {
+		once.Do(func() { close(done) })
+	}
+}
@@ -0,0 +1,76 @@
+package logs
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// 等待条件成立，超时则失败
+func waitFor(t *testing.T, cond func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatal("condition not met before deadline")
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestWatchLevelFile(t *testing.T) {
+	defer func(interval time.Duration) { levelFilePollInterval = interval }(levelFilePollInterval)
+	levelFilePollInterval = time.Millisecond
+
+	path := filepath.Join(t.TempDir(), "loglevel")
+	logger := NewLogger(LogLevelInfo, io.Discard)
+	stop := WatchLevelFile(logger, path)
+	defer stop()
+
+	if err := os.WriteFile(path, []byte("debug\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	waitFor(t, func() bool { return logger.Level() == LogLevelDebug })
+
+	if err := os.WriteFile(path, []byte("ERROR"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	waitFor(t, func() bool { return logger.Level() == LogLevelError })
+
+	// 非法内容不影响当前等级
+	if err := os.WriteFile(path, []byte("verbose"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	time.Sleep(10 * time.Millisecond)
+	if logger.Level() != LogLevelError {
+		t.Fatalf("invalid level should be ignored, got %s", logger.Level())
+	}
+}
+
+func TestSetLevelConcurrentWithNewGroup(t *testing.T) {
+	logger := NewLogger(LogLevelInfo, io.Discard)
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := 0; i < 1000; i++ {
+			logger.SetLevel(LogLevel(i % 4))
+			logger.SetEnabledLevels()
+		}
+	}()
+	for i := 0; i < 1000; i++ {
+		child := logger.NewGroup("i", i)
+		_ = logger.Merge(child)
+		_ = logger.WithDynamicField("n", func() any { return 0 })
+	}
+	<-done
+
+	// 子日志管理器的等级与父日志管理器相互独立
+	child := logger.NewGroup()
+	child.SetLevel(LogLevelKeyword)
+	logger.SetLevel(LogLevelDebug)
+	if child.Level() != LogLevelKeyword {
+		t.Fatalf("child level changed by parent: %s", child.Level())
+	}
+}
@@ -0,0 +1,38 @@
+package logs_test
+
+import (
+	"bytes"
+	"fmt"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/kkkunny/logs"
+)
+
+// 模拟业务代码中对日志管理器的包装
+func logViaWrapper(logger *logs.Logger) {
+	_ = logger.Info(0, "a", 1)
+}
+
+func TestAutoSkipWrapper(t *testing.T) {
+	var buf bytes.Buffer
+	logger := logs.NewLogger(logs.LogLevelDebug, &buf)
+	logger.SetAutoSkip(true)
+	logger.AddWrapperPrefix("github.com/kkkunny/logs_test.logViaWrapper")
+
+	_, file, line, _ := runtime.Caller(0)
+	logViaWrapper(logger)
+	if want := fmt.Sprintf("| %s:%d |", file, line+1); !strings.Contains(buf.String(), want) {
+		t.Fatalf("want caller %q in %q", want, buf.String())
+	}
+
+	// 未注册为包装函数时定位到包装函数内部
+	buf.Reset()
+	logger = logs.NewLogger(logs.LogLevelDebug, &buf)
+	logger.SetAutoSkip(true)
+	logViaWrapper(logger)
+	if !strings.Contains(buf.String(), "wrapper_test.go:") || strings.Contains(buf.String(), fmt.Sprintf(":%d |", line+1)) {
+		t.Fatalf("caller should be inside the wrapper: %q", buf.String())
+	}
+}